@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/executor"
+	"github.com/totti-rdz/tz/internal/history"
+)
+
+var againCmd = &cobra.Command{
+	Use:     "again",
+	Aliases: []string{"!!"},
+	Short:   "Rerun the last command of the current project",
+	Long: `Run the last mapped command of the current project again, with the
+same arguments. The command is resolved again, so changes to its mapping
+since then apply.
+
+Examples:
+  tz again    # Rerun the last command
+  tz '!!'     # Same, using alias (quoted so the shell doesn't expand it)`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Get current project root
+		projectPath, err := cfg.CurrentProjectPath()
+		if err != nil {
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
+
+		last, ok, err := history.Last(projectPath)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("no commands have run in this project yet")
+		}
+
+		fmt.Println("↻ tz", strings.TrimSpace(last.Name+" "+executor.QuoteArgs(last.Args)))
+		return dispatchMapped(last.Name, last.Args)
+	},
+}
+
+// dispatchMapped runs a built-in or custom command by name, as if it had
+// been typed on the command line
+func dispatchMapped(name string, args []string) error {
+	name = builtinName(name)
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name && config.IsBuiltinCommand(name) {
+			return c.RunE(c, args)
+		}
+	}
+	return HandleCustomCommand(name, args)
+}
+
+func init() {
+	rootCmd.AddCommand(againCmd)
+}
@@ -7,9 +7,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/totti-rdz/tz/internal/config"
-	"github.com/totti-rdz/tz/internal/detector"
-	"github.com/totti-rdz/tz/internal/executor"
-	"github.com/totti-rdz/tz/internal/prompt"
 )
 
 var (
@@ -28,49 +25,29 @@ Examples:
   tz clear     # Run the configured clear command
   tz c         # Same, using alias
   tz c -a      # Clear + remove lock files (package-lock.json, yarn.lock, etc.)`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get current project path
-		projectPath, err := config.GetCurrentProjectPath()
-		if err != nil {
-			return fmt.Errorf("failed to get current project path: %w", err)
-		}
-
+	DisableFlagParsing: true,
+	RunE: withMappedFlags(func(cmd *cobra.Command, args []string) error {
 		// Load config
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		// Get the command mapping
-		command, err := cfg.GetCommand(projectPath, "clear")
+		// Get current project root
+		projectPath, err := currentProject(cfg)
 		if err != nil {
-			// No mapping found - try auto-detection
-			suggestedCmd, projectType := detector.GetSuggestion(projectPath, "clear")
-
-			if suggestedCmd == "" || projectType == detector.Unknown {
-				return fmt.Errorf("no mapping found for 'clear' in this project\n\nTip: Run 'tz map clear \"<your-clear-command>\"' to set it up")
-			}
-
-			// Ask user for confirmation
-			if !prompt.ConfirmCommand(string(projectType), "clear", suggestedCmd) {
-				return fmt.Errorf("cancelled")
-			}
-
-			// Save the mapping
-			if err := cfg.SetCommand(projectPath, "clear", suggestedCmd); err != nil {
-				return fmt.Errorf("failed to save mapping: %w", err)
-			}
-
-			if err := cfg.Save(); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
-			}
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
 
-			command = suggestedCmd
-			fmt.Printf("✓ Saved mapping: clear -> \"%s\"\n\n", command)
+		// Get the command mapping (or an accepted auto-detected suggestion),
+		// with the arguments filled in
+		_, command, opts, err := prepareMapped(cfg, projectPath, "clear", args)
+		if err != nil {
+			return err
 		}
 
 		// Execute the mapped clear command
-		if err := executor.Execute(command); err != nil {
+		if err := runMapped(cfg, projectPath, "clear", command, opts); err != nil {
 			return err
 		}
 
@@ -89,6 +66,10 @@ Examples:
 			for _, lockFile := range lockFiles {
 				lockPath := filepath.Join(projectPath, lockFile)
 				if _, err := os.Stat(lockPath); err == nil {
+					if dryRunFlag {
+						fmt.Printf("Would remove %s\n", lockPath)
+						continue
+					}
 					fmt.Printf("Removing %s...\n", lockFile)
 					if err := os.Remove(lockPath); err != nil {
 						fmt.Printf("Warning: failed to remove %s: %v\n", lockFile, err)
@@ -98,10 +79,11 @@ Examples:
 		}
 
 		return nil
-	},
+	}),
 }
 
 func init() {
 	rootCmd.AddCommand(clearCmd)
 	clearCmd.Flags().BoolVarP(&clearAllFlag, "all", "a", false, "Also remove lock files (package-lock.json, yarn.lock, etc.)")
+	clearCmd.Flags().AddFlagSet(mappedFlags)
 }
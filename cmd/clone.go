@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -22,7 +23,7 @@ Examples:
   tz clone https://github.com/user/repo`,
 
 	Args: cobra.ExactArgs(1),
-	
+
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repoURL := args[0]
 
@@ -34,17 +35,18 @@ Examples:
 
 		// Clone the repository
 		fmt.Printf("Cloning %s...\n", repoURL)
-		cloneCmd := fmt.Sprintf("git clone %s", repoURL)
-		if err := executor.Execute(cloneCmd); err != nil {
+		cloneCmd := fmt.Sprintf("git clone %s", executor.Quote(repoURL))
+		if err := runShell(cloneCmd); err != nil {
 			return fmt.Errorf("failed to clone repository: %w", err)
 		}
 
 		// Open in VS Code
 		fmt.Printf("Opening %s in VS Code...\n", projectName)
-		codeCmd := fmt.Sprintf("code %s", projectName)
-		if err := executor.Execute(codeCmd); err != nil {
+		codeCmd := fmt.Sprintf("code %s", executor.Quote(projectName))
+		if err := runShell(codeCmd); err != nil {
 			// If 'code' command not found, provide helpful message
-			if strings.Contains(err.Error(), "command not found") || strings.Contains(err.Error(), "exit status 127") {
+			var exitErr *executor.ExitError
+			if errors.As(err, &exitErr) && exitErr.Code == 127 {
 				fmt.Printf("\n⚠ VS Code 'code' command not found in PATH.\n")
 				fmt.Printf("To enable it:\n")
 				fmt.Printf("  1. Open VS Code\n")
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+var (
+	migrateCheckFlag bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the tz config file",
+	Long:  `Manage ~/.tz/config.json, where all command mappings are stored.`,
+}
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the config file to the current format",
+	Long: `Upgrade ~/.tz/config.json to the format used by this version of tz.
+
+tz migrates older config files automatically when it loads them, keeping
+a copy of the old file in ~/.tz/config.json.bak. Use this command to run
+the migration explicitly, or --check to see what would change first.
+
+Examples:
+  tz config migrate           # Migrate now (with backup)
+  tz config migrate --check   # Only report pending migrations`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if migrateCheckFlag || dryRunFlag {
+			pending, err := config.PendingMigrations()
+			if err != nil {
+				return err
+			}
+
+			if len(pending) == 0 {
+				fmt.Printf("✓ Config is up to date (version %d)\n", config.CurrentVersion)
+				return nil
+			}
+
+			fmt.Println("Pending migrations:")
+			for _, step := range pending {
+				fmt.Printf("  %s\n", step)
+			}
+			fmt.Println("\nRun 'tz config migrate' to apply them.")
+			return nil
+		}
+
+		applied, err := config.Migrate()
+		if err != nil {
+			return fmt.Errorf("failed to migrate config: %w", err)
+		}
+
+		if len(applied) == 0 {
+			fmt.Printf("✓ Config is up to date (version %d)\n", config.CurrentVersion)
+			return nil
+		}
+
+		for _, step := range applied {
+			fmt.Printf("  %s\n", step)
+		}
+
+		backupPath, err := config.BackupPath()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Migrated config to version %d (backup: %s)\n", config.CurrentVersion, backupPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configMigrateCmd)
+	configMigrateCmd.Flags().BoolVar(&migrateCheckFlag, "check", false, "Report what would change without writing")
+}
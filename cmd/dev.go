@@ -2,13 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/totti-rdz/tz/internal/config"
-	"github.com/totti-rdz/tz/internal/detector"
-	"github.com/totti-rdz/tz/internal/executor"
-	"github.com/totti-rdz/tz/internal/prompt"
 )
 
 var devCmd = &cobra.Command{
@@ -23,61 +19,37 @@ Examples:
   tz dev              # Run the configured dev server
   tz d                # Same, using alias
   tz d --port 8080    # Pass custom arguments`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get current project path
-		projectPath, err := config.GetCurrentProjectPath()
-		if err != nil {
-			return fmt.Errorf("failed to get current project path: %w", err)
-		}
-
+	DisableFlagParsing: true,
+	RunE: withMappedFlags(func(cmd *cobra.Command, args []string) error {
 		// Load config
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		// Get the command mapping
-		command, err := cfg.GetCommand(projectPath, "dev")
+		// Get current project root
+		projectPath, err := currentProject(cfg)
 		if err != nil {
-			// No mapping found - try auto-detection
-			suggestedCmd, projectType := detector.GetSuggestion(projectPath, "dev")
-
-			if suggestedCmd == "" || projectType == detector.Unknown {
-				return fmt.Errorf("no mapping found for 'dev' in this project\n\nTip: Run 'tz map dev \"<your-dev-command>\"' to set it up")
-			}
-
-			// Ask user for confirmation
-			if !prompt.ConfirmCommand(string(projectType), "dev", suggestedCmd) {
-				return fmt.Errorf("cancelled")
-			}
-
-			// Save the mapping
-			if err := cfg.SetCommand(projectPath, "dev", suggestedCmd); err != nil {
-				return fmt.Errorf("failed to save mapping: %w", err)
-			}
-
-			if err := cfg.Save(); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
-			}
-
-			command = suggestedCmd
-			fmt.Printf("✓ Saved mapping: dev -> \"%s\"\n\n", command)
+			return fmt.Errorf("failed to get current project path: %w", err)
 		}
 
-		// Append any additional arguments
-		if len(args) > 0 {
-			command += " " + strings.Join(args, " ")
+		// Get the command mapping (or an accepted auto-detected suggestion),
+		// with the arguments filled in
+		_, command, opts, err := prepareMapped(cfg, projectPath, "dev", args)
+		if err != nil {
+			return err
 		}
 
 		// Execute the command
-		if err := executor.Execute(command); err != nil {
+		if err := runMapped(cfg, projectPath, "dev", command, opts); err != nil {
 			return err
 		}
 
 		return nil
-	},
+	}),
 }
 
 func init() {
 	rootCmd.AddCommand(devCmd)
+	devCmd.Flags().AddFlagSet(mappedFlags)
 }
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/detector"
+	"github.com/totti-rdz/tz/internal/executor"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <command> [args...]",
+	Short: "Show what a command would run and where its mapping comes from",
+	Long: `Resolve a built-in or custom command like running it would, and print
+the result without running anything: the shell command with arguments and
+placeholders filled in, the config layer or auto-detect rule it comes from,
+where and how it would run, and its dependencies.
+
+This is the same as running the command with --dry-run.
+
+Examples:
+  tz explain t -run TestLogin    # What would 'tz t -run TestLogin' run?
+  tz explain i -D vitest         # See the -D rewrite
+  tz explain c -a                # Including the lock files it would delete`,
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("requires a command to explain\n\nUsage: tz explain <command> [args...]")
+		}
+		if args[0] == "-h" || args[0] == "--help" {
+			return cmd.Help()
+		}
+
+		dryRunFlag = true
+		return dispatchMapped(args[0], args[1:])
+	},
+}
+
+// explainRun prints what running a mapped command would do, dependencies
+// first, without running anything
+func explainRun(cfg *config.Config, projectPath, commandName, command string, opts executor.Options) error {
+	graph, err := dependencyGraph(cfg, projectPath, commandName)
+	if err != nil {
+		return err
+	}
+
+	if len(graph) > 0 {
+		order := graph.Order()
+		fmt.Printf("%s depends on: %s\n\n", commandName, strings.Join(order, ", "))
+		for _, name := range order {
+			_, depCommand, depOpts, err := prepareMapped(cfg, projectPath, name, nil)
+			if err != nil {
+				return fmt.Errorf("failed to prepare dependency '%s' of %s: %w", name, commandName, err)
+			}
+			if err := describeRun(cfg, projectPath, name, depCommand, depOpts); err != nil {
+				return err
+			}
+			fmt.Println()
+		}
+	}
+
+	return describeRun(cfg, projectPath, commandName, command, opts)
+}
+
+// describeRun prints a single resolved command and how it would run
+func describeRun(cfg *config.Config, projectPath, commandName, command string, opts executor.Options) error {
+	fmt.Printf("Would run %s: %s\n", commandName, command)
+	fmt.Printf("  from: %s\n", resolutionSource(cfg, projectPath, commandName))
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = "the current directory"
+	}
+	fmt.Printf("  in: %s\n", dir)
+	for _, pair := range opts.Env {
+		fmt.Printf("  env: %s\n", pair)
+	}
+	if opts.Shell != "" {
+		fmt.Printf("  shell: %s\n", opts.Shell)
+	}
+
+	state, err := checkCache(cfg, projectPath, commandName, command, opts)
+	if err != nil {
+		return err
+	}
+	switch {
+	case state == nil:
+	case state.fresh && !noCacheFlag:
+		fmt.Printf("  cache: inputs unchanged, it would be skipped (--no-cache runs it anyway)\n")
+	default:
+		fmt.Printf("  cache: inputs changed since the last successful run\n")
+	}
+
+	if bgFlag {
+		fmt.Printf("  in the background (see 'tz ps')\n")
+	}
+	return nil
+}
+
+// resolutionSource explains where the mapping of a command comes from: a
+// config layer, or the auto-detect rule that suggests it
+func resolutionSource(cfg *config.Config, projectPath, commandName string) string {
+	if _, source, err := cfg.ResolveCommand(projectPath, commandName); err == nil {
+		return describeSource(cfg, source, projectPath)
+	}
+
+	if target, ok := detector.BuiltinTarget(projectPath, commandName); ok {
+		return fmt.Sprintf("auto-detected from %s, not saved yet", target.Source)
+	}
+
+	projectType, marker := detector.Detect(projectPath)
+	if projectType == detector.NodeJS {
+		setup := detector.DetectNodeSetup(projectPath)
+		return fmt.Sprintf("auto-detected %s project (found %s), using %s, not saved yet", projectType, marker, setup)
+	}
+	if projectType == detector.Python {
+		return fmt.Sprintf("auto-detected %s project (found %s), using %s, not saved yet", projectType, marker, detector.DetectPythonSetup(projectPath))
+	}
+	if projectType != detector.Unknown {
+		return fmt.Sprintf("auto-detected %s project (found %s), not saved yet", projectType, marker)
+	}
+	return "unknown"
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
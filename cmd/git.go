@@ -14,7 +14,7 @@ var fetchCmd = &cobra.Command{
 	Short:   "Git fetch",
 	Long:    `Run git fetch to update remote tracking branches.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executor.Execute("git fetch")
+		return runShell("git fetch")
 	},
 }
 
@@ -35,11 +35,11 @@ Examples:
 
 		// Special case: switch to previous branch
 		if branchName == "-" {
-			return executor.Execute("git checkout -")
+			return runShell("git checkout -")
 		}
 
 		// Normal case: create and checkout new branch
-		return executor.Execute(fmt.Sprintf("git checkout -b %s", branchName))
+		return runShell(fmt.Sprintf("git checkout -b %s", executor.Quote(branchName)))
 	},
 }
 
@@ -50,10 +50,19 @@ var statusCmd = &cobra.Command{
 	Short:   "Git status",
 	Long:    `Show the working tree status (git status).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return executor.Execute("git status")
+		return runShell("git status")
 	},
 }
 
+// runShell runs a shell command, or only shows it with --dry-run
+func runShell(command string) error {
+	if dryRunFlag {
+		fmt.Printf("Would run: %s\n", command)
+		return nil
+	}
+	return executor.Execute(command)
+}
+
 func init() {
 	rootCmd.AddCommand(fetchCmd)
 	rootCmd.AddCommand(branchCmd)
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/executor"
+	"github.com/totti-rdz/tz/internal/history"
+)
+
+var (
+	historyProjectFlag bool
+	historyFailedFlag  bool
+	historyLimitFlag   int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the mapped commands tz ran",
+	Long: `Show the most recent runs of mapped commands, across all projects.
+
+Every run is recorded in ~/.tz/history.jsonl with its project, command,
+arguments, start time, duration and exit code.
+
+Examples:
+  tz history                # Recent runs everywhere
+  tz history --project      # Only the current project
+  tz history --failed       # Only runs that failed
+  tz history --limit 0      # Everything`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := ""
+		if historyProjectFlag {
+			// Load config
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Get current project root
+			projectPath, err = cfg.CurrentProjectPath()
+			if err != nil {
+				return fmt.Errorf("failed to get current project path: %w", err)
+			}
+		}
+
+		shown, err := history.Recent(historyLimitFlag, func(e history.Entry) bool {
+			return (projectPath == "" || e.Project == projectPath) && (!historyFailedFlag || e.ExitCode != 0)
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(shown) == 0 {
+			fmt.Println("No matching runs in the history.")
+			return nil
+		}
+
+		for _, e := range shown {
+			status := "✓"
+			if e.ExitCode != 0 {
+				status = fmt.Sprintf("✗ %d", e.ExitCode)
+			}
+			invocation := e.Name
+			if len(e.Args) > 0 {
+				invocation += " " + executor.QuoteArgs(e.Args)
+			}
+			if e.DependencyOf != "" {
+				invocation += " (for " + e.DependencyOf + ")"
+			}
+			fmt.Printf("  %s  %-16s %-30s %8s  %s\n",
+				e.Start.Format("2006-01-02 15:04"),
+				filepath.Base(e.Project),
+				invocation,
+				e.Duration.Round(10*time.Millisecond),
+				status)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().BoolVar(&historyProjectFlag, "project", false, "Only show runs in the current project")
+	historyCmd.Flags().BoolVar(&historyFailedFlag, "failed", false, "Only show runs that failed")
+	historyCmd.Flags().IntVar(&historyLimitFlag, "limit", 20, "Number of runs to show (0 for all)")
+}
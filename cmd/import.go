@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/detector"
+)
+
+var importSharedFlag bool
+
+var importCmd = &cobra.Command{
+	Use:   "import <make|just|task>",
+	Short: "Map the targets of a Makefile, justfile or Taskfile",
+	Long: `Map the targets of the project's Makefile, justfile or Taskfile without
+asking.
+
+Targets that match a built-in command (e.g. 'test', 'clean') are mapped
+to it; all other targets become custom commands of the same name, with
+the target's comment as description. Commands that are already mapped
+or clash with tz's own commands are skipped.
+
+Examples:
+  tz import make            # tz t runs "make test", tz lint runs "make lint"
+  tz import just --shared   # Write the mappings to .tz.json
+  tz import task --dry-run  # Show what would be mapped`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"make", "just", "task"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner, ok := detector.FindTaskRunner(args[0])
+		if !ok {
+			return fmt.Errorf("unknown task runner '%s' (expected make, just or task)", args[0])
+		}
+
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Get current project root
+		projectPath, err := currentProject(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
+
+		file := runner.File(projectPath)
+		if file == "" {
+			return fmt.Errorf("no %s found in this project", strings.Join(runner.Files, ", "))
+		}
+
+		// Built-ins first, then the remaining targets
+		var commands []detector.CustomCommand
+		builtins := runner.Builtins(projectPath)
+		for _, name := range config.BuiltinCommands {
+			if c, ok := builtins[name]; ok {
+				commands = append(commands, c)
+			}
+		}
+		commands = append(commands, runner.Others(projectPath)...)
+
+		if len(commands) == 0 {
+			fmt.Printf("No targets found in %s\n", file)
+			return nil
+		}
+
+		imported := make(map[string]config.Command)
+		var names []string
+		for _, c := range commands {
+			// "gen:proto" and "gen-proto" both become gen-proto; the first wins
+			if _, ok := imported[c.Name]; ok {
+				fmt.Printf("  ⊘ Skipped: %s (\"%s\" already imported as %s)\n", c.Command, imported[c.Name].Run, c.Name)
+				continue
+			}
+			if existing, err := cfg.GetCommand(projectPath, c.Name); err == nil {
+				fmt.Printf("  ⊘ Skipped: %s (already mapped to \"%s\")\n", c.Name, existing.Run)
+				continue
+			}
+			if !config.IsBuiltinCommand(c.Name) && isTzCommand(c.Name) {
+				fmt.Printf("  ⊘ Skipped: %s (clashes with 'tz %s')\n", c.Name, c.Name)
+				continue
+			}
+			imported[c.Name] = config.Command{Run: c.Command, Description: c.Description}
+			names = append(names, c.Name)
+		}
+
+		if len(names) == 0 {
+			fmt.Printf("Nothing to import from %s\n", file)
+			return nil
+		}
+
+		if dryRunFlag {
+			fmt.Printf("Would map from %s:\n", file)
+			for _, name := range names {
+				fmt.Printf("  %s -> \"%s\"\n", name, imported[name].Run)
+			}
+			return nil
+		}
+
+		// Save the mappings
+		if importSharedFlag {
+			for _, name := range names {
+				if err := cfg.SetSharedCommand(projectPath, name, imported[name]); err != nil {
+					return fmt.Errorf("failed to set shared command: %w", err)
+				}
+			}
+		} else if err := cfg.Update(func(c *config.Config) error {
+			for _, name := range names {
+				if err := c.SetCommand(projectPath, name, imported[name]); err != nil {
+					return fmt.Errorf("failed to set command: %w", err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		fmt.Printf("✓ Imported %d commands from %s:\n", len(names), file)
+		for _, name := range names {
+			fmt.Printf("  %s -> \"%s\"\n", name, imported[name].Run)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().BoolVarP(&importSharedFlag, "shared", "s", false, "Write the mappings to the project's .tz.json (shared with your team)")
+}
@@ -25,37 +25,43 @@ You can accept suggestions, provide your own, or skip commands.
 Example:
   tz init     # Start interactive setup`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get current project path
-		projectPath, err := config.GetCurrentProjectPath()
-		if err != nil {
-			return fmt.Errorf("failed to get current project path: %w", err)
-		}
-
 		// Load config
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		// Get current project root
+		projectPath, err := currentProject(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
+
 		// Detect project type
 		projectType := detector.DetectProjectType(projectPath)
 
 		fmt.Printf("Initializing tz for current project:\n  %s\n\n", projectPath)
 
-		if projectType != detector.Unknown {
+		if projectType == detector.NodeJS {
+			setup := detector.DetectNodeSetup(projectPath)
+			fmt.Printf("Detected: %s project, using %s\n\n", projectType, setup)
+			warnLockfileConflict(projectPath, projectType)
+		} else if projectType == detector.Python {
+			fmt.Printf("Detected: %s project, using %s\n\n", projectType, detector.DetectPythonSetup(projectPath))
+		} else if projectType != detector.Unknown {
 			fmt.Printf("Detected: %s project\n\n", projectType)
 		} else {
 			fmt.Printf("Project type: Unknown (manual configuration required)\n\n")
 		}
 
 		reader := bufio.NewReader(os.Stdin)
-		commands := []string{"install", "dev", "test", "build", "clear"}
+		selected := make(map[string]config.Command)
 
-		for _, commandName := range commands {
+		for _, commandName := range config.BuiltinCommands {
 			// Check if already configured
 			existingCmd, err := cfg.GetCommand(projectPath, commandName)
-			if err == nil && existingCmd != "" {
-				fmt.Printf("✓ '%s' already configured: %s\n", commandName, existingCmd)
+			if err == nil && !existingCmd.IsZero() {
+				fmt.Printf("✓ '%s' already configured: %s\n", commandName, existingCmd.Run)
 				fmt.Print("  Keep this? (y/n): ")
 				response, _ := reader.ReadString('\n')
 				response = strings.ToLower(strings.TrimSpace(response))
@@ -103,28 +109,86 @@ Example:
 
 			// Save the command if provided
 			if selectedCommand != "" {
-				if err := cfg.SetCommand(projectPath, commandName, selectedCommand); err != nil {
-					return fmt.Errorf("failed to set command: %w", err)
-				}
+				selected[commandName] = config.Command{Run: selectedCommand}
 				fmt.Printf("  ✓ Saved: %s -> \"%s\"\n", commandName, selectedCommand)
 			} else {
 				fmt.Printf("  ⊘ Skipped: %s\n", commandName)
 			}
 		}
 
+		// Offer the project's other scripts and targets (lint, e2e, ...) as
+		// custom commands
+		scripts := detector.OtherCommands(projectPath)
+		source := ""
+		for _, script := range scripts {
+			if script.Source != source {
+				source = script.Source
+				fmt.Printf("\nOther commands in %s:\n", source)
+			}
+			if _, ok := selected[script.Name]; ok {
+				continue
+			}
+			if _, err := cfg.GetCommand(projectPath, script.Name); err == nil {
+				fmt.Printf("✓ '%s' already configured\n", script.Name)
+				continue
+			}
+			if isTzCommand(script.Name) {
+				fmt.Printf("  ⊘ Skipped: %s (clashes with 'tz %s')\n", script.Name, script.Name)
+				continue
+			}
+
+			fmt.Printf("Add 'tz %s' -> \"%s\"? (y/n): ", script.Name, script.Command)
+			response, _ := reader.ReadString('\n')
+			response = strings.ToLower(strings.TrimSpace(response))
+			if response == "y" || response == "yes" || response == "" {
+				selected[script.Name] = config.Command{Run: script.Command, Description: script.Description}
+				fmt.Printf("  ✓ Saved: %s -> \"%s\"\n", script.Name, script.Command)
+			} else {
+				fmt.Printf("  ⊘ Skipped: %s\n", script.Name)
+			}
+		}
+
+		if dryRunFlag {
+			fmt.Printf("\nDry run: nothing was saved.\n")
+			return nil
+		}
+
 		// Save config
-		if err := cfg.Save(); err != nil {
+		if err := cfg.Update(func(c *config.Config) error {
+			for commandName, command := range selected {
+				if err := c.SetCommand(projectPath, commandName, command); err != nil {
+					return fmt.Errorf("failed to set command: %w", err)
+				}
+			}
+			return nil
+		}); err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 
 		fmt.Printf("\n✓ Configuration complete!\n")
 		fmt.Printf("\nYou can now use:\n")
 		fmt.Printf("  tz i (install), tz d (dev), tz t (test), tz b (build), tz c (clear)\n")
+		for _, script := range scripts {
+			if selected[script.Name].Run == script.Command {
+				fmt.Printf("  tz %s\n", script.Name)
+			}
+		}
 
 		return nil
 	},
 }
 
+// isTzCommand reports whether name is one of tz's own commands or aliases,
+// which a custom command of the same name could never be reached through
+func isTzCommand(name string) bool {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	rootCmd.AddCommand(initCmd)
 }
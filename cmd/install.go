@@ -2,13 +2,10 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/totti-rdz/tz/internal/config"
 	"github.com/totti-rdz/tz/internal/detector"
-	"github.com/totti-rdz/tz/internal/executor"
-	"github.com/totti-rdz/tz/internal/prompt"
 )
 
 var (
@@ -29,75 +26,51 @@ Examples:
   tz i express         # Install express package
   tz i express axios   # Install multiple packages
   tz i -D nodemon      # Install as dev dependency (npm/yarn/pnpm/bun only)`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Get current project path
-		projectPath, err := config.GetCurrentProjectPath()
-		if err != nil {
-			return fmt.Errorf("failed to get current project path: %w", err)
-		}
-
+	DisableFlagParsing: true,
+	RunE: withMappedFlags(func(cmd *cobra.Command, args []string) error {
 		// Load config
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
-		// Get the command mapping
-		command, err := cfg.GetCommand(projectPath, "install")
+		// Get current project root
+		projectPath, err := currentProject(cfg)
 		if err != nil {
-			// No mapping found - try auto-detection
-			suggestedCmd, projectType := detector.GetSuggestion(projectPath, "install")
-
-			if suggestedCmd == "" || projectType == detector.Unknown {
-				return fmt.Errorf("no mapping found for 'install' in this project\n\nTip: Run 'tz map install \"<your-install-command>\"' to set it up")
-			}
-
-			// Ask user for confirmation
-			if !prompt.ConfirmCommand(string(projectType), "install", suggestedCmd) {
-				return fmt.Errorf("cancelled")
-			}
-
-			// Save the mapping
-			if err := cfg.SetCommand(projectPath, "install", suggestedCmd); err != nil {
-				return fmt.Errorf("failed to save mapping: %w", err)
-			}
-
-			if err := cfg.Save(); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
-			}
-
-			command = suggestedCmd
-			fmt.Printf("✓ Saved mapping: install -> \"%s\"\n\n", command)
+			return fmt.Errorf("failed to get current project path: %w", err)
 		}
 
-		// Append any package names or additional arguments
-		if len(args) > 0 {
-			command += " " + strings.Join(args, " ")
+		// Get the command mapping (or an accepted auto-detected suggestion),
+		// with package names and other arguments filled in
+		_, command, opts, err := prepareMapped(cfg, projectPath, "install", args)
+		if err != nil {
+			return err
 		}
 
-		// Handle -D flag for dev dependencies
+		// With -D the packages are added as dev dependencies by the
+		// project's package manager, e.g. "yarn add -D vitest"
 		if installDevFlag {
-			if strings.Contains(command, "npm") || strings.Contains(command, "pnpm") {
-				command += " --save-dev"
-			} else if strings.Contains(command, "yarn") {
-				command += " --dev"
-			} else if strings.Contains(command, "bun") {
-				command += " -D"
-			} else {
+			if detector.DetectProjectType(projectPath) != detector.NodeJS {
 				return fmt.Errorf("-D flag is only supported for npm/yarn/pnpm/bun projects\nCurrent command: %s", command)
 			}
+			addDev := config.Command{Run: detector.DetectNodeSetup(projectPath).Manager.AddDev()}
+			command, err = expandArgs(projectPath, addDev, args)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Execute the command
-		if err := executor.Execute(command); err != nil {
+		if err := runMapped(cfg, projectPath, "install", command, opts); err != nil {
 			return err
 		}
 
 		return nil
-	},
+	}),
 }
 
 func init() {
 	rootCmd.AddCommand(installCmd)
 	installCmd.Flags().BoolVarP(&installDevFlag, "dev", "D", false, "Install as dev dependency (npm/yarn/pnpm/bun)")
+	installCmd.Flags().AddFlagSet(mappedFlags)
 }
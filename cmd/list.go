@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List commands available in the current project",
+	Long: `List every command mapping available in the current project and
+where it was resolved from.
+
+Personal project mappings take priority over the project's shared
+.tz.json, which takes priority over global commands. Only the mapping
+that would actually run is shown.
+
+Examples:
+  tz list     # Show all mapped commands and their source
+  tz ls       # Same, using alias`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Get current project root
+		projectPath, err := currentProject(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
+
+		commands := cfg.ListCommands(projectPath)
+		if len(commands) == 0 {
+			fmt.Println("No commands mapped for this project.")
+			fmt.Println("\nTip: Run 'tz init' or 'tz map <command> \"<shell-command>\"' to set one up")
+			return nil
+		}
+
+		for _, c := range commands {
+			fmt.Printf("  %-12s %-10s %s\n", c.Name, "["+string(c.Source)+"]", c.Command.Run)
+			if c.Command.Description != "" {
+				fmt.Printf("  %-12s %-10s # %s\n", "", "", c.Command.Description)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/totti-rdz/tz/internal/executor"
 )
 
 var (
@@ -66,7 +65,7 @@ Examples:
 			}
 		}
 
-		if err := executor.Execute(gitCmd); err != nil {
+		if err := runShell(gitCmd); err != nil {
 			return err
 		}
 
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/runlog"
+)
+
+var logsFollowFlag bool
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <name> | logs last [command]",
+	Short: "Show the output of a background command or a logged run",
+	Long: `Show the log of a command started with --bg.
+
+With --follow, new output is shown as it is written until the command
+exits or you press Ctrl-C.
+
+'tz logs last' shows the most recent log of a run with --log (or of a
+mapping with logging turned on) in the current project, optionally of a
+specific command. Logs are kept in ~/.tz/logs/<project>/.
+
+Examples:
+  tz logs dev        # Show the dev server's output so far
+  tz logs -f dev     # Keep following it
+  tz logs last       # Output of the last logged run
+  tz logs last test  # Output of the last logged test run`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if args[0] == "last" {
+			name := ""
+			if len(args) == 2 {
+				name = builtinName(args[1])
+			}
+			return showLastLog(name)
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("accepts 1 arg(s), received %d", len(args))
+		}
+
+		p, err := findBackground(args[0])
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(p.Log)
+		if err != nil {
+			return fmt.Errorf("failed to open log: %w", err)
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(os.Stdout, file); err != nil {
+			return fmt.Errorf("failed to read log: %w", err)
+		}
+		if !logsFollowFlag {
+			return nil
+		}
+
+		for {
+			running := p.Running()
+
+			// The log starts over when the command is restarted
+			if info, err := file.Stat(); err == nil {
+				if offset, _ := file.Seek(0, io.SeekCurrent); info.Size() < offset {
+					file.Seek(0, io.SeekStart)
+				}
+			}
+			if _, err := io.Copy(os.Stdout, file); err != nil {
+				return fmt.Errorf("failed to read log: %w", err)
+			}
+
+			if !running {
+				fmt.Fprintf(os.Stderr, "tz: %s exited\n", p.Name)
+				return nil
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+	},
+}
+
+// showLastLog prints the most recent run log of the current project
+func showLastLog(name string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Get current project root
+	projectPath, err := cfg.CurrentProjectPath()
+	if err != nil {
+		return fmt.Errorf("failed to get current project path: %w", err)
+	}
+
+	path, err := runlog.Last(projectPath, name)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	defer file.Close()
+
+	fmt.Fprintf(os.Stderr, "==> %s <==\n", path)
+	if _, err := io.Copy(os.Stdout, file); err != nil {
+		return fmt.Errorf("failed to read log: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.Flags().BoolVarP(&logsFollowFlag, "follow", "f", false, "Keep showing new output")
+}
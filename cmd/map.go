@@ -2,13 +2,25 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/totti-rdz/tz/internal/config"
 )
 
 var (
-	globalFlag bool
+	globalFlag   bool
+	sharedFlag   bool
+	mapEnvFlag   []string
+	mapCwdFlag   string
+	mapShellFlag string
+	mapDescFlag  string
+	mapOSFlag    []string
+	mapRawFlag   bool
+	mapDepsFlag  []string
+	mapInFlag    []string
+	mapOutFlag   []string
+	mapLogFlag   bool
 )
 
 var mapCmd = &cobra.Command{
@@ -19,11 +31,33 @@ var mapCmd = &cobra.Command{
 The mapping will be saved in ~/.tz/config.json and associated with 
 the current directory (or globally if --global flag is used).
 
+With --shared the mapping is written to .tz.json in the project root
+instead, so it can be committed and shared with your team. Personal
+mappings in ~/.tz/config.json take priority over shared ones.
+
 Built-in commands (install, dev, test, build, clear) can only be mapped 
-per-project and cannot be set as global commands.
+per-project and cannot be set as global commands. They can also be given
+by their aliases (i, d, t, b, c). Names of other tz commands, like logs or
+run, can't be mapped.
 
 You can also create custom commands with any name you want.
 
+Mappings can carry extra settings: environment variables (--env), a
+working directory relative to the project root (--cwd), the shell that
+runs them (--shell), a description shown by 'tz list' (--desc) and the
+operating systems they apply to (--platform).
+
+Arguments passed to a mapped command are shell-quoted, so 'TestFoo|TestBar'
+or file names with spaces arrive as typed. Use --raw for mappings that
+should let the shell expand their arguments (globs, pipes, variables).
+
+With --depends-on, other commands run before this one: each of them once,
+after their own dependencies, with independent ones running in parallel.
+
+With --inputs, tz hashes the matching files and skips the command when
+they are unchanged since its last successful run (use --no-cache to run it
+anyway). --outputs makes it rerun when the files it produces are missing.
+
 Examples:
   tz map install "npm install"
   tz map dev "npm run start"
@@ -31,47 +65,76 @@ Examples:
   tz map clear "rm -rf dist"
   tz map docker "docker-compose up"
   tz map seed "node scripts/seed.js"
-  tz map --global mouflon "/path/to/mouflon.ts"`,
+  tz map --global mouflon "/path/to/mouflon.ts"
+  tz map --shared test "go test ./..."
+  tz map dev "npm run dev" --cwd web --env PORT=3000 --desc "Start the web app"
+  tz map e2e "npm run e2e" --depends-on build,db
+  tz map install "npm ci" --inputs package-lock.json --outputs node_modules`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		commandName := args[0]
+		commandName := builtinName(args[0])
 		shellCommand := args[1]
 
+		// 'tz logs' would always run tz's own command, never the mapping
+		if !config.IsBuiltinCommand(commandName) && isTzCommand(commandName) {
+			return fmt.Errorf("'%s' is a tz command, so a mapping with that name could never run\n\nTip: Pick another name, e.g. 'tz map my-%s ...'", commandName, commandName)
+		}
+
+		mapping, err := buildMapping(shellCommand)
+		if err != nil {
+			return err
+		}
+
 		// Load config
 		cfg, err := config.Load()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
 
+		if dryRunFlag {
+			layer := "for this project"
+			if globalFlag {
+				layer = "globally"
+			} else if sharedFlag {
+				layer = "in the project's .tz.json"
+			}
+			fmt.Printf("Would map '%s' to '%s' %s\n", commandName, shellCommand, layer)
+			return nil
+		}
+
 		// Handle global command mapping
 		if globalFlag {
-			if err := cfg.SetGlobalCommand(commandName, shellCommand); err != nil {
+			if err := cfg.Update(func(c *config.Config) error {
+				return c.SetGlobalCommand(commandName, mapping)
+			}); err != nil {
 				return fmt.Errorf("failed to set global command: %w", err)
 			}
 
-			// Save config
-			if err := cfg.Save(); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
-			}
-
 			fmt.Printf("✓ Mapped '%s' to '%s' globally\n", commandName, shellCommand)
 			return nil
 		}
 
 		// Handle project-specific command mapping
-		projectPath, err := config.GetCurrentProjectPath()
+		projectPath, err := currentProject(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to get current project path: %w", err)
 		}
 
-		// Set the command mapping
-		if err := cfg.SetCommand(projectPath, commandName, shellCommand); err != nil {
-			return fmt.Errorf("failed to set command: %w", err)
+		// Handle shared (team) command mapping
+		if sharedFlag {
+			if err := cfg.SetSharedCommand(projectPath, commandName, mapping); err != nil {
+				return fmt.Errorf("failed to set shared command: %w", err)
+			}
+
+			fmt.Printf("✓ Mapped '%s' to '%s' in:\n  %s\n", commandName, shellCommand, config.SharedPath(projectPath))
+			return nil
 		}
 
-		// Save config
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
+		// Set the command mapping and save config
+		if err := cfg.Update(func(c *config.Config) error {
+			return c.SetCommand(projectPath, commandName, mapping)
+		}); err != nil {
+			return fmt.Errorf("failed to set command: %w", err)
 		}
 
 		fmt.Printf("✓ Mapped '%s' to '%s' for project:\n  %s\n", commandName, shellCommand, projectPath)
@@ -79,7 +142,48 @@ Examples:
 	},
 }
 
+// buildMapping creates a command mapping from the shell command and flags
+func buildMapping(shellCommand string) (config.Command, error) {
+	mapping := config.Command{
+		Run:         shellCommand,
+		Cwd:         mapCwdFlag,
+		Shell:       mapShellFlag,
+		Description: mapDescFlag,
+		Platforms:   mapOSFlag,
+		Raw:         mapRawFlag,
+		DependsOn:   mapDepsFlag,
+		Inputs:      mapInFlag,
+		Outputs:     mapOutFlag,
+		Log:         mapLogFlag,
+	}
+
+	for _, pair := range mapEnvFlag {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return config.Command{}, fmt.Errorf("invalid --env value %q (expected KEY=VALUE)", pair)
+		}
+		if mapping.Env == nil {
+			mapping.Env = make(map[string]string)
+		}
+		mapping.Env[key] = value
+	}
+
+	return mapping, nil
+}
+
 func init() {
 	rootCmd.AddCommand(mapCmd)
 	mapCmd.Flags().BoolVarP(&globalFlag, "global", "g", false, "Set command as a global command (not project-specific)")
+	mapCmd.Flags().BoolVarP(&sharedFlag, "shared", "s", false, "Write the mapping to the project's .tz.json (shared with your team)")
+	mapCmd.MarkFlagsMutuallyExclusive("global", "shared")
+	mapCmd.Flags().StringArrayVarP(&mapEnvFlag, "env", "e", nil, "Set an environment variable for the command (KEY=VALUE, repeatable)")
+	mapCmd.Flags().StringVar(&mapCwdFlag, "cwd", "", "Run the command in this directory (relative to the project root)")
+	mapCmd.Flags().StringVar(&mapShellFlag, "shell", "", "Shell that runs the command (default: sh)")
+	mapCmd.Flags().StringVar(&mapDescFlag, "desc", "", "Description shown by 'tz list' and 'tz which'")
+	mapCmd.Flags().StringSliceVar(&mapOSFlag, "platform", nil, "Only run on these operating systems (e.g. linux,darwin)")
+	mapCmd.Flags().BoolVar(&mapRawFlag, "raw", false, "Pass arguments to the shell unquoted (allow globs, pipes and variables)")
+	mapCmd.Flags().StringSliceVar(&mapDepsFlag, "depends-on", nil, "Commands to run first (e.g. install,db)")
+	mapCmd.Flags().StringSliceVar(&mapInFlag, "inputs", nil, "Skip the command while these files are unchanged (globs, e.g. go.sum,src/**)")
+	mapCmd.Flags().StringSliceVar(&mapOutFlag, "outputs", nil, "Files the command produces; rerun it when they are missing (globs)")
+	mapCmd.Flags().BoolVar(&mapLogFlag, "log", false, "Keep the output of every run in ~/.tz/logs (see 'tz logs last')")
 }
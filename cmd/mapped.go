@@ -0,0 +1,561 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/totti-rdz/tz/internal/background"
+	"github.com/totti-rdz/tz/internal/cache"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/detector"
+	"github.com/totti-rdz/tz/internal/executor"
+	"github.com/totti-rdz/tz/internal/git"
+	"github.com/totti-rdz/tz/internal/history"
+	"github.com/totti-rdz/tz/internal/placeholder"
+	"github.com/totti-rdz/tz/internal/prompt"
+	"github.com/totti-rdz/tz/internal/runlog"
+)
+
+var (
+	hereFlag    bool
+	graceFlag   time.Duration
+	bgFlag      bool
+	noCacheFlag bool
+	dryRunFlag  bool
+	logFlag     bool
+
+	// typedArgs are the arguments of the mapped command as typed, tz flags
+	// included, so 'tz again' can repeat the invocation
+	typedArgs []string
+)
+
+// mappedFlags are the flags of every command that runs a mapping. Built-in
+// commands add them to their own flags; custom commands are parsed against
+// them in HandleCustomCommand. They are set up before any init() runs.
+var mappedFlags = newMappedFlags()
+
+func newMappedFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("mapped", pflag.ContinueOnError)
+	fs.BoolVar(&bgFlag, "bg", false, "Run the command in the background (see 'tz ps')")
+	fs.BoolVar(&noCacheFlag, "no-cache", false, "Run the command even if its inputs are unchanged (--force before the command name)")
+	fs.BoolVar(&logFlag, "log", false, "Also write the output to a log file (see 'tz logs last')")
+	return fs
+}
+
+// currentProject returns the root of the current project. When the project
+// isn't configured yet but another checkout of the same repository is, the
+// user is asked whether to adopt that checkout's mappings.
+func currentProject(cfg *config.Config) (string, error) {
+	projectPath, err := cfg.CurrentProjectPath()
+	if err != nil {
+		return "", err
+	}
+
+	if _, exists := cfg.Projects[projectPath]; exists {
+		return projectPath, nil
+	}
+
+	remote := cfg.ProjectRemote(projectPath)
+	matchPath, ok := cfg.FindProjectByRemote(remote, projectPath)
+	if !ok || !prompt.IsInteractive() || dryRunFlag {
+		// Non-interactive runs still use the other checkout's mappings through
+		// the remote fallback in GetCommand, without recording anything
+		return projectPath, nil
+	}
+
+	fmt.Printf("\nFound mappings for %s from another checkout:\n  %s\n\n", remote, matchPath)
+	adopt := prompt.Confirm("Adopt them for this checkout?")
+
+	if err := cfg.Update(func(c *config.Config) error {
+		if adopt {
+			c.AdoptProject(matchPath, projectPath)
+		} else {
+			// Start this checkout with its own (empty) mappings so we don't ask again
+			c.Projects[projectPath] = config.ProjectConfig{Remote: remote}
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if adopt {
+		fmt.Printf("✓ Adopted mappings for project:\n  %s\n\n", projectPath)
+	}
+
+	return projectPath, nil
+}
+
+// resolveMappedCommand returns the command mapped to commandName in the
+// project. If there is no mapping, it offers the auto-detected suggestion
+// and saves it when the user accepts.
+func resolveMappedCommand(cfg *config.Config, projectPath, commandName string) (config.Command, error) {
+	command, err := cfg.GetCommand(projectPath, commandName)
+	if err == nil {
+		return command, nil
+	}
+
+	// No mapping found - try auto-detection
+	suggestedCmd, projectType := detector.GetSuggestion(projectPath, commandName)
+
+	if suggestedCmd == "" || projectType == detector.Unknown {
+		return config.Command{}, fmt.Errorf("no mapping found for '%s' in this project\n\nTip: Run 'tz map %s \"<your-%s-command>\"' to set it up", commandName, commandName, commandName)
+	}
+
+	// A dry run shows the suggestion without asking or saving
+	if dryRunFlag {
+		return config.Command{Run: suggestedCmd}, nil
+	}
+
+	warnLockfileConflict(projectPath, projectType)
+
+	// Ask user for confirmation
+	if !prompt.ConfirmCommand(string(projectType), commandName, suggestedCmd) {
+		return config.Command{}, fmt.Errorf("cancelled")
+	}
+
+	// Save the mapping
+	if err := cfg.Update(func(c *config.Config) error {
+		return c.SetCommand(projectPath, commandName, config.Command{Run: suggestedCmd})
+	}); err != nil {
+		return config.Command{}, fmt.Errorf("failed to save mapping: %w", err)
+	}
+
+	fmt.Printf("✓ Saved mapping: %s -> \"%s\"\n\n", commandName, suggestedCmd)
+	return config.Command{Run: suggestedCmd}, nil
+}
+
+// warnLockfileConflict warns when a Node.js project has lockfiles of
+// several package managers, since the suggestion may pick the wrong one
+func warnLockfileConflict(projectPath string, projectType detector.ProjectType) {
+	if projectType != detector.NodeJS {
+		return
+	}
+	if setup := detector.DetectNodeSetup(projectPath); setup.Conflict != "" {
+		fmt.Fprintf(os.Stderr, "⚠ Warning: %s\n", setup.Conflict)
+	}
+}
+
+// builtinName resolves a built-in command's alias (i, d, t, b, c) to its
+// name. Other names are returned as they are.
+func builtinName(name string) string {
+	for _, c := range rootCmd.Commands() {
+		if config.IsBuiltinCommand(c.Name()) && (c.Name() == name || c.HasAlias(name)) {
+			return c.Name()
+		}
+	}
+	return name
+}
+
+// prepareMapped resolves the mapped command name (or built-in alias) in
+// the project and returns the command's name, the shell command with args
+// filled in and the options to run it with. Built-ins go through the
+// auto-detect suggestion when they aren't mapped yet.
+func prepareMapped(cfg *config.Config, projectPath, name string, args []string) (string, string, executor.Options, error) {
+	name = builtinName(name)
+
+	var mapping config.Command
+	var err error
+	if config.IsBuiltinCommand(name) {
+		mapping, err = resolveMappedCommand(cfg, projectPath, name)
+		if err != nil {
+			return "", "", executor.Options{}, err
+		}
+	} else {
+		mapping, err = cfg.GetCommand(projectPath, name)
+		if err != nil {
+			return "", "", executor.Options{}, fmt.Errorf("unknown command '%s'\n\nTip: Run 'tz map %s \"<your-command>\"' to set it up", name, name)
+		}
+	}
+
+	opts, err := runOptions(projectPath, name, mapping)
+	if err != nil {
+		return "", "", executor.Options{}, err
+	}
+
+	// Fill in placeholders, or append the arguments
+	command, err := expandArgs(projectPath, mapping, args)
+	if err != nil {
+		return "", "", executor.Options{}, err
+	}
+
+	return name, command, opts, nil
+}
+
+// runOptions returns how a mapped command should be launched: in the
+// project root (or its cwd setting) with the mapping's env and shell
+func runOptions(projectPath, commandName string, command config.Command) (executor.Options, error) {
+	if !command.SupportsPlatform() {
+		return executor.Options{}, fmt.Errorf("'%s' is not available on %s (platforms: %s)", commandName, runtime.GOOS, strings.Join(command.Platforms, ", "))
+	}
+
+	dir := workDir(projectPath)
+	if dir != "" && command.Cwd != "" {
+		if filepath.IsAbs(command.Cwd) {
+			dir = command.Cwd
+		} else {
+			dir = filepath.Join(dir, command.Cwd)
+		}
+	}
+
+	return executor.Options{
+		Dir:   dir,
+		Env:   command.EnvList(),
+		Shell: command.Shell,
+		Grace: graceFlag,
+	}, nil
+}
+
+// expandArgs fills the placeholders in a mapped command with args and the
+// project's named values. Commands without placeholders get the arguments
+// appended, as before placeholders existed. Values are shell-quoted unless
+// the mapping is raw.
+func expandArgs(projectPath string, mapping config.Command, args []string) (string, error) {
+	command := mapping.Run
+	join := executor.QuoteArgs
+	quote := func(value string, ctx placeholder.Context) string {
+		switch ctx {
+		case placeholder.SingleQuoted:
+			return executor.EscapeSingle(value)
+		case placeholder.DoubleQuoted:
+			return executor.EscapeDouble(value)
+		}
+		return executor.Quote(value)
+	}
+	if mapping.Raw {
+		join = func(values []string) string { return strings.Join(values, " ") }
+		quote = func(value string, ctx placeholder.Context) string { return value }
+	}
+
+	if !placeholder.HasPlaceholders(command) {
+		if len(args) > 0 {
+			command += " " + join(args)
+		}
+		return command, nil
+	}
+
+	return placeholder.Expand(command, args, func(name string) (string, error) {
+		var value string
+		var err error
+		switch name {
+		case "project":
+			value = filepath.Base(projectPath)
+		case "root":
+			value = projectPath
+		case "cwd":
+			value, err = config.GetCurrentProjectPath()
+		case "branch":
+			value, err = git.CurrentBranch(projectPath)
+		default:
+			err = fmt.Errorf("unknown placeholder")
+		}
+		return value, err
+	}, quote)
+}
+
+// runMapped runs a resolved mapped command in the foreground, or starts it
+// in the background when --bg is set. The commands it depends on run first.
+func runMapped(cfg *config.Config, projectPath, commandName, command string, opts executor.Options) error {
+	if dryRunFlag {
+		return explainRun(cfg, projectPath, commandName, command, opts)
+	}
+
+	start := time.Now()
+	if err := runDependencies(cfg, projectPath, commandName, nil); err != nil {
+		// Record the command as typed too, so 'tz again' retries all of it
+		// rather than only the dependency that failed
+		recordRun(history.Entry{
+			Project:  projectPath,
+			Name:     commandName,
+			Command:  command,
+			Args:     typedArgs,
+			Start:    start,
+			Duration: time.Since(start),
+			ExitCode: exitCode(err),
+		})
+		return err
+	}
+
+	if bgFlag {
+		return startBackground(projectPath, commandName, command, opts)
+	}
+
+	cached, err := cachedRun(cfg, projectPath, commandName, command, opts, func() error {
+		return execute(cfg, projectPath, commandName, command, typedArgs, "", opts)
+	})
+	if cached {
+		fmt.Printf("✓ %s: cached, inputs unchanged since the last successful run (use --no-cache to run it anyway)\n", commandName)
+	}
+	return err
+}
+
+// execute runs a mapped command and records the run in the history, marked
+// as a dependency of dependencyOf if it is one. With --log or a mapping
+// that asks for it, the output is also written to a log.
+func execute(cfg *config.Config, projectPath, commandName, command string, args []string, dependencyOf string, opts executor.Options) error {
+	// Commands without a mapping (e.g. auto-detected ones) get a zero mapping
+	mapping, _ := cfg.GetCommand(projectPath, commandName)
+
+	var logFile *runlog.File
+	if logFlag || mapping.Log {
+		var err error
+		if logFile, err = runlog.Create(projectPath, commandName); err != nil {
+			return err
+		}
+		defer logFile.Close()
+		opts = teeOutput(opts, logFile)
+	}
+
+	start := time.Now()
+	err := executor.ExecuteWith(command, opts)
+
+	entry := history.Entry{
+		Project:      projectPath,
+		Name:         commandName,
+		Command:      command,
+		Args:         args,
+		Start:        start,
+		Duration:     time.Since(start),
+		ExitCode:     exitCode(err),
+		DependencyOf: dependencyOf,
+	}
+	if logFile != nil {
+		entry.Log = logFile.Path()
+		fmt.Fprintf(os.Stderr, "tz: output saved to %s\n", logFile.Path())
+	}
+	recordRun(entry)
+
+	return err
+}
+
+// recordRun adds a run to the history. Failing to record it is reported
+// but doesn't fail the command.
+func recordRun(entry history.Entry) {
+	if err := history.Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "tz: failed to record history: %v\n", err)
+	}
+}
+
+// teeOutput sends the command's output to log as well as where it would go
+// otherwise. The command keeps reading from the terminal.
+func teeOutput(opts executor.Options, log io.Writer) executor.Options {
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil && stderr == nil {
+		stdout, stderr = os.Stdout, os.Stderr
+		opts.Stdin = os.Stdin
+	}
+
+	// A shared writer (the prefixed output of 'tz run -p') stays shared, so
+	// os/exec keeps writing to it from one goroutine at a time
+	opts.Stdout = io.MultiWriter(stdout, log)
+	if stderr == stdout {
+		opts.Stderr = opts.Stdout
+	} else {
+		opts.Stderr = io.MultiWriter(stderr, log)
+	}
+	return opts
+}
+
+// exitCode returns the exit code tz reports for a command's result. Errors
+// that aren't exit codes (e.g. the shell couldn't start) count as 1.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *executor.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// inputsCache is the cache state of a mapped command that declares inputs
+type inputsCache struct {
+	mapping config.Command
+	dir     string // Directory the input and output globs are relative to
+	key     string // Hash of the command and its inputs
+	fresh   bool   // Whether the key matches the last successful run
+}
+
+// checkCache hashes the inputs of a mapped command and compares them with
+// its last successful run. It returns nil if the mapping declares no inputs.
+func checkCache(cfg *config.Config, projectPath, commandName, command string, opts executor.Options) (*inputsCache, error) {
+	mapping, err := cfg.GetCommand(projectPath, commandName)
+	if err != nil || len(mapping.Inputs) == 0 {
+		return nil, nil
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+	}
+
+	key, err := cache.Key(dir, command, opts.Env, mapping.Inputs)
+	var noMatch *cache.NoMatchError
+	if errors.As(err, &noMatch) {
+		fmt.Fprintf(os.Stderr, "tz: %v, so %s always runs (check its --inputs)\n", err, commandName)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash inputs of %s: %w", commandName, err)
+	}
+
+	return &inputsCache{
+		mapping: mapping,
+		dir:     dir,
+		key:     key,
+		fresh:   cache.Fresh(projectPath, commandName, key, dir, mapping.Outputs),
+	}, nil
+}
+
+// cachedRun calls run unless the mapping declares inputs that are unchanged
+// since its last successful run (and its outputs still exist). It reports
+// whether the run was skipped.
+func cachedRun(cfg *config.Config, projectPath, commandName, command string, opts executor.Options, run func() error) (bool, error) {
+	state, err := checkCache(cfg, projectPath, commandName, command, opts)
+	if err != nil {
+		return false, err
+	}
+	if state == nil {
+		return false, run()
+	}
+	if state.fresh && !noCacheFlag {
+		return true, nil
+	}
+
+	if err := run(); err != nil {
+		return false, err
+	}
+
+	// Hash again, the command may have changed its own inputs (e.g. a lock file)
+	key, err := cache.Key(state.dir, command, opts.Env, state.mapping.Inputs)
+	if err == nil {
+		err = cache.Record(projectPath, commandName, key)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tz: failed to update the cache of %s: %v\n", commandName, err)
+	}
+	return false, nil
+}
+
+// startBackground starts a mapped command detached from the terminal
+func startBackground(projectPath, commandName, command string, opts executor.Options) error {
+	p, err := background.Start(projectPath, commandName, command, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Started %s in the background (pid %d)\n", commandName, p.PID)
+	fmt.Printf("  Logs: tz logs -f %s\n", commandName)
+	fmt.Printf("  Stop: tz stop %s\n", commandName)
+	return nil
+}
+
+// workDir returns the directory mapped commands should run in: the project
+// root, or the current directory when --here is set (which also overrides
+// a mapping's cwd)
+func workDir(projectPath string) string {
+	if hereFlag {
+		return ""
+	}
+	return projectPath
+}
+
+// withMappedFlags wraps the RunE of a command that runs a mapping. Such
+// commands disable cobra's flag parsing so flags like -run or --port reach
+// the underlying tool; tz's own flags are picked out here instead.
+func withMappedFlags(run func(cmd *cobra.Command, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		typedArgs = args
+		args, err := parseMappedFlags(args, cmd.Flags(), cmd.InheritedFlags())
+		if errors.Is(err, pflag.ErrHelp) {
+			return cmd.Help()
+		}
+		if err != nil {
+			return err
+		}
+		return run(cmd, args)
+	}
+}
+
+// parseMappedFlags sets the tz flags found in args and returns the other
+// arguments. Unknown flags are kept as arguments, and scanning stops at
+// "--" so a flag with the same name as a tz flag can still be passed on.
+// -h/--help only asks for help when one of the flag sets has a help flag
+// (cobra adds one to built-ins); custom commands pass it to the tool.
+func parseMappedFlags(args []string, flagSets ...*pflag.FlagSet) ([]string, error) {
+	lookup := func(name string, short bool) *pflag.Flag {
+		for _, fs := range flagSets {
+			var flag *pflag.Flag
+			if short {
+				flag = fs.ShorthandLookup(name)
+			} else {
+				flag = fs.Lookup(name)
+			}
+			if flag != nil {
+				return flag
+			}
+		}
+		return nil
+	}
+
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			rest = append(rest, arg)
+			continue
+		}
+
+		long := strings.HasPrefix(arg, "--")
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if (name == "help" || (!long && name == "h")) && lookup("help", false) != nil {
+			return nil, pflag.ErrHelp
+		}
+
+		var flag *pflag.Flag
+		if long {
+			flag = lookup(name, false)
+		} else if len(name) == 1 {
+			flag = lookup(name, true)
+		}
+		if flag == nil {
+			// Not ours, pass it through (e.g. -run or --port)
+			rest = append(rest, arg)
+			continue
+		}
+
+		if !hasValue {
+			switch {
+			case flag.NoOptDefVal != "":
+				// Boolean flags don't take a value
+				value = flag.NoOptDefVal
+			case i+1 < len(args):
+				i++
+				value = args[i]
+			default:
+				return nil, fmt.Errorf("flag needs an argument: %s", arg)
+			}
+		}
+
+		if err := flag.Value.Set(value); err != nil {
+			return nil, fmt.Errorf("invalid argument %q for %s: %w", value, arg, err)
+		}
+		flag.Changed = true
+	}
+
+	return rest, nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/totti-rdz/tz/internal/executor"
+)
+
+// flagState is what parseMappedFlags sets
+type flagState struct {
+	bg, noCache, log, here, dryRun bool
+	grace                          time.Duration
+}
+
+// resetMappedFlags sets the tz flags back to their defaults
+func resetMappedFlags() {
+	for _, fs := range []*pflag.FlagSet{rootCmd.PersistentFlags(), mappedFlags} {
+		fs.VisitAll(func(f *pflag.Flag) {
+			f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	}
+}
+
+func TestParseMappedFlags(t *testing.T) {
+	defaults := flagState{grace: executor.DefaultGrace}
+
+	tests := []struct {
+		name  string
+		args  []string
+		want  []string
+		state flagState
+	}{
+		{
+			name:  "plain arguments",
+			args:  []string{"./pkg", "-v"},
+			want:  []string{"./pkg", "-v"},
+			state: defaults,
+		},
+		{
+			name:  "tz flags anywhere",
+			args:  []string{"--bg", "serve", "--no-cache", "--log", "--here", "--dry-run"},
+			want:  []string{"serve"},
+			state: flagState{bg: true, noCache: true, log: true, here: true, dryRun: true, grace: executor.DefaultGrace},
+		},
+		{
+			name:  "grace with equals",
+			args:  []string{"--grace=1s", "x"},
+			want:  []string{"x"},
+			state: flagState{grace: time.Second},
+		},
+		{
+			name:  "grace with separate value",
+			args:  []string{"--grace", "1s", "x"},
+			want:  []string{"x"},
+			state: flagState{grace: time.Second},
+		},
+		{
+			name:  "boolean with value",
+			args:  []string{"--bg=false", "x"},
+			want:  []string{"x"},
+			state: defaults,
+		},
+		{
+			name:  "unknown flags passed through",
+			args:  []string{"--port", "3000", "-run", "TestX", "--watchAll=false", "-"},
+			want:  []string{"--port", "3000", "-run", "TestX", "--watchAll=false", "-"},
+			state: defaults,
+		},
+		{
+			name:  "double dash stops parsing",
+			args:  []string{"--log", "--", "--bg", "--", "x"},
+			want:  []string{"--bg", "--", "x"},
+			state: flagState{log: true, grace: executor.DefaultGrace},
+		},
+		{
+			name:  "help goes to the tool",
+			args:  []string{"-h", "--help"},
+			want:  []string{"-h", "--help"},
+			state: defaults,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetMappedFlags()
+			defer resetMappedFlags()
+
+			got, err := parseMappedFlags(tt.args, rootCmd.PersistentFlags(), mappedFlags)
+			if err != nil {
+				t.Fatalf("parseMappedFlags() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMappedFlags() = %q, want %q", got, tt.want)
+			}
+			state := flagState{bgFlag, noCacheFlag, logFlag, hereFlag, dryRunFlag, graceFlag}
+			if state != tt.state {
+				t.Errorf("flags = %+v, want %+v", state, tt.state)
+			}
+		})
+	}
+}
+
+func TestParseMappedFlagsErrors(t *testing.T) {
+	withHelp := pflag.NewFlagSet("built-in", pflag.ContinueOnError)
+	withHelp.BoolP("help", "h", false, "help")
+
+	tests := []struct {
+		name     string
+		args     []string
+		flagSets []*pflag.FlagSet
+		want     error // nil for any error
+	}{
+		{"missing value", []string{"x", "--grace"}, nil, nil},
+		{"invalid value", []string{"--grace=soon"}, nil, nil},
+		{"help of a built-in", []string{"./...", "-h"}, []*pflag.FlagSet{withHelp}, pflag.ErrHelp},
+		{"long help of a built-in", []string{"--help"}, []*pflag.FlagSet{withHelp}, pflag.ErrHelp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetMappedFlags()
+			defer resetMappedFlags()
+
+			flagSets := append([]*pflag.FlagSet{rootCmd.PersistentFlags(), mappedFlags}, tt.flagSets...)
+			_, err := parseMappedFlags(tt.args, flagSets...)
+			if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("parseMappedFlags() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/background"
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+var psCmd = &cobra.Command{
+	Use:   "ps",
+	Short: "List commands running in the background",
+	Long: `List the commands started with --bg, across all projects.
+
+Commands that exited since they were started are shown once, then
+forgotten. Their logs stay available with 'tz logs' until the command is
+started again.
+
+Examples:
+  tz d --bg    # Start the dev server in the background
+  tz ps        # See what's running`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		processes, err := background.List()
+		if err != nil {
+			return fmt.Errorf("failed to list background processes: %w", err)
+		}
+
+		if len(processes) == 0 {
+			fmt.Println("No background processes.")
+			fmt.Println("\nTip: Add --bg to any mapped command to run it in the background")
+			return nil
+		}
+
+		fmt.Printf("  %-12s %-16s %-8s %-14s %s\n", "NAME", "PROJECT", "PID", "STATUS", "COMMAND")
+		for _, p := range processes {
+			status := "running " + time.Since(p.Started).Round(time.Second).String()
+			if !p.Running() {
+				status = "exited"
+				p.Forget()
+			}
+			fmt.Printf("  %-12s %-16s %-8d %-14s %s\n", p.Name, filepath.Base(p.Project), p.PID, status, p.Command)
+		}
+
+		return nil
+	},
+}
+
+// findBackground returns the background process with the given name,
+// looking in the current project first and then in the others, as long as
+// the name is unambiguous there
+func findBackground(name string) (*background.Process, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	projectPath, err := cfg.CurrentProjectPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current project path: %w", err)
+	}
+
+	p, err := background.Find(projectPath, name)
+	if err != nil || p != nil {
+		return p, err
+	}
+
+	all, err := background.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*background.Process
+	for _, other := range all {
+		if other.Name == name {
+			matches = append(matches, other)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no background process named '%s'\n\nTip: Run 'tz ps' to see what's running", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("'%s' is running in several projects; run this from the project's directory", name)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(psCmd)
+}
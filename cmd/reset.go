@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/totti-rdz/tz/internal/executor"
 )
 
 var resetCmd = &cobra.Command{
@@ -42,7 +41,7 @@ Examples:
 		// Build and execute the git reset command
 		gitCmd := fmt.Sprintf("git reset --soft HEAD~%d", count)
 
-		if err := executor.Execute(gitCmd); err != nil {
+		if err := runShell(gitCmd); err != nil {
 			return err
 		}
 
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -32,37 +33,114 @@ Universal git shortcuts work everywhere:
 
 // Execute runs the root command
 func Execute() {
+	args, err := leadingFlags(os.Args[1:])
+	if err != nil {
+		exit(err)
+	}
+	rootCmd.SetArgs(args)
+
 	// Temporarily capture stderr to check for unknown command errors
-	err := rootCmd.Execute()
+	err = rootCmd.Execute()
 	if err != nil {
-		// Check if it's an "unknown command" error
+		// Check if it's cobra's "unknown command" error (ours quote the name
+		// differently and must not be mistaken for it)
 		errStr := err.Error()
-		if strings.Contains(errStr, "unknown command") {
+		if strings.HasPrefix(errStr, "unknown command \"") {
 			// Extract the command name from the error message
 			// Error format: "unknown command \"docker\" for \"tz\""
 			parts := strings.Split(errStr, "\"")
 			if len(parts) >= 2 {
 				commandName := parts[1]
-				// Get remaining args from os.Args
-				args := os.Args[2:] // Skip program name and command name
+				// Get remaining args. Global flags may come before the
+				// command name ("tz --dry-run docker"), so only the name
+				// itself is dropped.
+				args := withoutFirst(args, commandName)
 
 				// Try to run as custom command
 				if customErr := HandleCustomCommand(commandName, args); customErr != nil {
-					fmt.Fprintln(os.Stderr, customErr)
-					os.Exit(1)
+					exit(customErr)
 				}
 				return // Success - don't print the original error
 			}
 		}
 
 		// Other errors - print and exit
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exit(err)
+	}
+}
+
+// exit terminates tz after a failed command. When a mapped command failed,
+// tz exits with the command's own exit code; its output already explains
+// the failure, so only a signal is reported.
+func exit(err error) {
+	var exitErr *executor.ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.Signal != nil {
+			fmt.Fprintln(os.Stderr, exitErr)
+		}
+		os.Exit(exitErr.Code)
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+// leadingAliases are alternative names of tz flags that tz only accepts
+// before the command name. After it they are common flags of the tools tz
+// runs (e.g. "go test -n", "npm install --force"), so they are passed on.
+var leadingAliases = map[string]string{
+	"-n":      "dry-run",
+	"--force": "no-cache",
+}
+
+// leadingFlags sets the flags in leadingAliases that come before the
+// command name and returns the other arguments
+func leadingFlags(args []string) ([]string, error) {
+	// Not nil: cobra would fall back to os.Args
+	rest := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if name, ok := leadingAliases[arg]; ok {
+			fs := rootCmd.PersistentFlags()
+			if fs.Lookup(name) == nil {
+				fs = mappedFlags
+			}
+			if err := fs.Set(name, "true"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if arg == "--" || len(arg) < 2 || arg[0] != '-' {
+			// The command name (or the end of tz's flags); the rest is its own
+			return append(rest, args[i:]...), nil
+		}
+
+		rest = append(rest, arg)
+		// Keep the value of a flag like "--grace 5s" with it
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if flag := rootCmd.PersistentFlags().Lookup(name); flag != nil && !hasValue && flag.NoOptDefVal == "" && i+1 < len(args) {
+			i++
+			rest = append(rest, args[i])
+		}
 	}
+	return rest, nil
+}
+
+// withoutFirst returns args without the first occurrence of arg
+func withoutFirst(args []string, arg string) []string {
+	for i, a := range args {
+		if a == arg {
+			return append(append([]string{}, args[:i]...), args[i+1:]...)
+		}
+	}
+	return args
 }
 
 func init() {
-	// Global flags can be added here
+	// Global flags
+	rootCmd.PersistentFlags().BoolVar(&hereFlag, "here", false, "Run mapped commands in the current directory instead of the project root")
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "Show what would run without running or changing anything (-n before the command name)")
+	rootCmd.PersistentFlags().DurationVar(&graceFlag, "grace", executor.DefaultGrace, "Time an interrupted command gets to exit before it is killed")
 
 	// Silence Cobra's error output so we can handle unknown commands gracefully
 	rootCmd.SilenceErrors = true
@@ -71,31 +149,34 @@ func init() {
 
 // HandleCustomCommand tries to execute a command as a custom mapped command
 func HandleCustomCommand(commandName string, args []string) error {
-	// Get current project path
-	projectPath, err := config.GetCurrentProjectPath()
-	if err != nil {
-		return fmt.Errorf("failed to get current project path: %w", err)
-	}
-
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Try to get the custom command
-	command, err := cfg.GetCommand(projectPath, commandName)
+	// Get current project root
+	projectPath, err := currentProject(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get current project path: %w", err)
+	}
+
+	// Cobra doesn't parse flags for unknown commands, so pick up tz's global
+	// and mapped-command flags ourselves
+	typedArgs = args
+	args, err = parseMappedFlags(args, rootCmd.PersistentFlags(), mappedFlags)
 	if err != nil {
-		return fmt.Errorf("unknown command '%s'\n\nTip: Run 'tz map %s \"<your-command>\"' to set it up", commandName, commandName)
+		return err
 	}
 
-	// Append any additional arguments
-	if len(args) > 0 {
-		command += " " + strings.Join(args, " ")
+	// Get the custom command, with any additional arguments filled in
+	commandName, command, opts, err := prepareMapped(cfg, projectPath, commandName, args)
+	if err != nil {
+		return err
 	}
 
 	// Execute the custom command
-	if err := executor.Execute(command); err != nil {
+	if err := runMapped(cfg, projectPath, commandName, command, opts); err != nil {
 		return err
 	}
 
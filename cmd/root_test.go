@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLeadingFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		want        []string
+		wantDryRun  bool
+		wantNoCache bool
+	}{
+		{"before the command", []string{"-n", "t", "./..."}, []string{"t", "./..."}, true, false},
+		{"after other tz flags", []string{"--here", "--grace", "5s", "-n", "docker"}, []string{"--here", "--grace", "5s", "docker"}, true, false},
+		{"after the command", []string{"t", "-n", "4"}, []string{"t", "-n", "4"}, false, false},
+		{"grace value isn't the command", []string{"--grace", "-n", "t"}, []string{"--grace", "-n", "t"}, false, false},
+		{"after --", []string{"--", "-n"}, []string{"--", "-n"}, false, false},
+		{"alone", []string{"-n"}, []string{}, true, false},
+		{"force before the command", []string{"--force", "b"}, []string{"b"}, false, true},
+		{"force after the command", []string{"i", "--force"}, []string{"i", "--force"}, false, false},
+		{"both", []string{"--force", "-n", "b"}, []string{"b"}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetMappedFlags()
+			defer resetMappedFlags()
+
+			got, err := leadingFlags(tt.args)
+			if err != nil {
+				t.Fatalf("leadingFlags() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("leadingFlags() = %q, want %q", got, tt.want)
+			}
+			if dryRunFlag != tt.wantDryRun || noCacheFlag != tt.wantNoCache {
+				t.Errorf("dry run, no cache = %v, %v, want %v, %v", dryRunFlag, noCacheFlag, tt.wantDryRun, tt.wantNoCache)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/executor"
+	"github.com/totti-rdz/tz/internal/output"
+	"github.com/totti-rdz/tz/internal/tasks"
+)
+
+var (
+	runParallelFlag   bool
+	runKillOthersFlag bool
+)
+
+// job is a mapped command ready to run as part of 'tz run'
+type job struct {
+	name         string
+	command      string
+	opts         executor.Options
+	dependencyOf string // Command(s) the job only runs for, empty if it was asked for
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <command>...",
+	Short: "Run several mapped commands in a row or at once",
+	Long: `Run several built-in or custom commands of the current project.
+
+By default the commands run one after another, stopping at the first one
+that fails, and a summary of the steps is printed at the end. Built-in
+commands can be given by their aliases. Dependencies (--depends-on) run
+before the commands that need them, each one only once per 'tz run'.
+
+With --parallel, the commands run concurrently and their output is shown
+with a colored name prefix. tz exits with the worst result: if any command
+fails, tz fails with its exit code. With --kill-others, the first failure
+stops the remaining commands.
+
+Examples:
+  tz run i b t                   # Install, build and test
+  tz run -p api web worker       # Start three services side by side
+  tz run -p -k api web worker    # Stop them all when one of them fails`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Get current project root
+		projectPath, err := currentProject(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
+
+		// Resolve every command up front, so nothing starts if one is missing
+		// and any auto-detect prompts happen before output gets multiplexed
+		var jobs []job
+		for _, name := range args {
+			name, command, opts, err := prepareMapped(cfg, projectPath, name, nil)
+			if err != nil {
+				return err
+			}
+			jobs = append(jobs, job{name: name, command: command, opts: opts})
+		}
+
+		if dryRunFlag {
+			for i, j := range jobs {
+				if i > 0 {
+					fmt.Println()
+				}
+				if err := explainRun(cfg, projectPath, j.name, j.command, j.opts); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if runParallelFlag {
+			return runParallel(cfg, projectPath, jobs, runKillOthersFlag)
+		}
+		return runSequence(cfg, projectPath, jobs)
+	},
+}
+
+// run runs the job unless its inputs are unchanged (see cachedRun). It
+// reports whether the run was skipped.
+func (j *job) run(cfg *config.Config, projectPath string) (bool, error) {
+	return cachedRun(cfg, projectPath, j.name, j.command, j.opts, func() error {
+		return execute(cfg, projectPath, j.name, j.command, nil, j.dependencyOf, j.opts)
+	})
+}
+
+// prefixOutput sends the job's output to stdout with its name in front,
+// colored by its position i and padded to width. The returned writer must
+// be flushed once the job is done.
+func (j *job) prefixOutput(i, width int, mu *sync.Mutex) *output.PrefixWriter {
+	prefix := output.Colorize(i, fmt.Sprintf("%-*s |", width, j.name)) + " "
+	w := output.NewPrefixWriter(os.Stdout, mu, prefix)
+	j.opts.Stdout = w
+	j.opts.Stderr = w
+	return w
+}
+
+// mappedDependencies returns a function that looks up the direct
+// dependencies of a mapped command, for building task graphs
+func mappedDependencies(cfg *config.Config, projectPath string) func(name string) ([]string, error) {
+	return func(name string) ([]string, error) {
+		mapping, err := cfg.GetCommand(projectPath, name)
+		if err != nil {
+			// Unmapped commands have no dependencies; preparing them below
+			// reports the missing mapping (or offers a suggestion)
+			return nil, nil
+		}
+		deps := make([]string, len(mapping.DependsOn))
+		for i, dep := range mapping.DependsOn {
+			deps[i] = builtinName(dep)
+		}
+		return deps, nil
+	}
+}
+
+// dependencyGraph returns the graph of the commands commandName depends on,
+// directly or indirectly, without commandName itself
+func dependencyGraph(cfg *config.Config, projectPath, commandName string) (tasks.Graph, error) {
+	graph, err := tasks.Build(commandName, mappedDependencies(cfg, projectPath))
+	if err != nil {
+		return nil, err
+	}
+	return graph.Without(commandName), nil
+}
+
+// runDependencies runs the commands commandName depends on in dependency
+// order. Commands that don't depend on each other run in parallel with
+// prefixed output. Commands in done have already run and are skipped; the
+// ones that succeed are added to it (done may be nil).
+func runDependencies(cfg *config.Config, projectPath, commandName string, done map[string]bool) error {
+	graph, err := dependencyGraph(cfg, projectPath, commandName)
+	if err != nil {
+		return err
+	}
+	for name := range done {
+		graph = graph.Without(name)
+	}
+	if len(graph) == 0 {
+		return nil
+	}
+
+	// Resolve every dependency up front, like 'tz run' does
+	order := graph.Order()
+	jobs := map[string]*job{}
+	width := 0
+	for _, name := range order {
+		_, command, opts, err := prepareMapped(cfg, projectPath, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to prepare dependency '%s' of %s: %w", name, commandName, err)
+		}
+		jobs[name] = &job{name: name, command: command, opts: opts, dependencyOf: commandName}
+		width = max(width, len(name))
+	}
+
+	fmt.Printf("▶ %s depends on: %s\n", commandName, strings.Join(order, ", "))
+
+	var mu sync.Mutex
+	err = graph.Run(func(name string) error {
+		j := jobs[name]
+		w := j.prefixOutput(slices.Index(order, name), width, &mu)
+
+		cached, err := j.run(cfg, projectPath)
+		w.Flush()
+		switch {
+		case err != nil:
+			w.Printf("✗ %v", err)
+			return err
+		case cached:
+			w.Printf("✓ cached")
+		default:
+			w.Printf("✓ finished")
+		}
+		if done != nil {
+			mu.Lock()
+			done[name] = true
+			mu.Unlock()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// runSequence runs the jobs one after another until one fails, then prints
+// a summary of the steps. Each job's dependencies run before it, unless an
+// earlier step already ran them.
+func runSequence(cfg *config.Config, projectPath string, jobs []job) error {
+	durations := make([]time.Duration, len(jobs))
+	cached := make([]bool, len(jobs))
+	var failed error
+	done := 0
+	ran := map[string]bool{}
+
+	for i, j := range jobs {
+		if i > 0 {
+			fmt.Println()
+		}
+		start := time.Now()
+		if failed = runDependencies(cfg, projectPath, j.name, ran); failed == nil {
+			fmt.Printf("▶ %s: %s\n", j.name, j.command)
+			cached[i], failed = j.run(cfg, projectPath)
+		}
+		durations[i] = time.Since(start)
+		if failed == nil {
+			ran[j.name] = true
+		}
+		if cached[i] {
+			fmt.Println("✓ cached, inputs unchanged since the last successful run")
+		}
+		done++
+		if failed != nil {
+			break
+		}
+	}
+
+	width := 0
+	for _, j := range jobs {
+		width = max(width, len(j.name))
+	}
+
+	fmt.Println("\nSummary:")
+	for i, j := range jobs {
+		switch {
+		case i >= done:
+			fmt.Printf("  - %-*s  %8s\n", width, j.name, "skipped")
+		case i == done-1 && failed != nil:
+			fmt.Printf("  ✗ %-*s  %8s  %v\n", width, j.name, durations[i].Round(10*time.Millisecond), failed)
+		case cached[i]:
+			fmt.Printf("  ✓ %-*s  %8s\n", width, j.name, "cached")
+		default:
+			fmt.Printf("  ✓ %-*s  %8s\n", width, j.name, durations[i].Round(10*time.Millisecond))
+		}
+	}
+
+	return failed
+}
+
+// runParallel runs the jobs concurrently with prefixed output and returns
+// the worst failure. The jobs and everything they depend on form one
+// graph, so a job starts once its dependencies have succeeded and a shared
+// dependency runs once. After a failure no new commands are started. With
+// killOthers, the first failure also stops the running ones; their own
+// results then don't count.
+func runParallel(cfg *config.Config, projectPath string, jobs []job, killOthers bool) error {
+	names := make([]string, len(jobs))
+	all := map[string]*job{}
+	for i := range jobs {
+		names[i] = jobs[i].name
+		all[jobs[i].name] = &jobs[i]
+	}
+	requested := strings.Join(names, ", ")
+
+	graph, err := tasks.BuildAll(names, mappedDependencies(cfg, projectPath))
+	if err != nil {
+		return err
+	}
+
+	// Resolve the dependencies up front, like the jobs themselves. The jobs
+	// keep their colors; dependencies come after them.
+	for _, name := range graph.Order() {
+		if all[name] != nil {
+			continue
+		}
+		_, command, opts, err := prepareMapped(cfg, projectPath, name, nil)
+		if err != nil {
+			return fmt.Errorf("failed to prepare dependency '%s': %w", name, err)
+		}
+		all[name] = &job{name: name, command: command, opts: opts, dependencyOf: requested}
+		names = append(names, name)
+	}
+
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, j := range jobs {
+		if deps := graph[j.name]; len(deps) > 0 {
+			fmt.Printf("▶ %s depends on: %s\n", j.name, strings.Join(deps, ", "))
+		}
+	}
+
+	var mu sync.Mutex
+	var resultsMu sync.Mutex
+	var stopOnce sync.Once
+	stop := make(chan struct{})
+	results := map[string]error{}
+
+	graph.Run(func(name string) error {
+		j := all[name]
+		w := j.prefixOutput(slices.Index(names, name), width, &mu)
+		j.opts.Stop = stop
+
+		cached, err := j.run(cfg, projectPath)
+		w.Flush()
+
+		select {
+		case <-stop:
+			if err != nil {
+				w.Printf("stopped")
+				return err
+			}
+		default:
+		}
+
+		resultsMu.Lock()
+		results[name] = err
+		resultsMu.Unlock()
+
+		switch {
+		case cached:
+			w.Printf("✓ cached")
+		case err == nil:
+			w.Printf("✓ finished")
+		default:
+			w.Printf("✗ %v", err)
+			if killOthers {
+				stopOnce.Do(func() { close(stop) })
+			}
+		}
+		return err
+	})
+
+	worst := make([]error, 0, len(results))
+	for _, err := range results {
+		worst = append(worst, err)
+	}
+	return worstResult(worst)
+}
+
+// worstResult returns the failure with the highest exit code
+func worstResult(results []error) error {
+	var worst error
+	worstCode := 0
+	for _, err := range results {
+		if code := exitCode(err); code > worstCode {
+			worst, worstCode = err, code
+		}
+	}
+	return worst
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().BoolVarP(&runParallelFlag, "parallel", "p", false, "Run the commands concurrently")
+	runCmd.Flags().BoolVarP(&runKillOthersFlag, "kill-others", "k", false, "Stop the other commands when one fails (with --parallel)")
+	runCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Run commands even if their inputs are unchanged")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/background"
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+var stopCmd = &cobra.Command{
+	Use:   "stop <name|all>",
+	Short: "Stop a command running in the background",
+	Long: `Stop a command started with --bg, along with any processes it started.
+
+The command gets SIGTERM and is killed if it hasn't exited after the
+grace period (see --grace). 'all' stops every background command of the
+current project.
+
+Examples:
+  tz stop dev    # Stop the background dev server
+  tz stop all    # Stop everything started in this project`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var processes []*background.Process
+
+		if args[0] == "all" {
+			// Load config
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Get current project root
+			projectPath, err := cfg.CurrentProjectPath()
+			if err != nil {
+				return fmt.Errorf("failed to get current project path: %w", err)
+			}
+
+			processes, err = background.ForProject(projectPath)
+			if err != nil {
+				return fmt.Errorf("failed to list background processes: %w", err)
+			}
+			if len(processes) == 0 {
+				fmt.Println("Nothing is running in the background for this project.")
+				return nil
+			}
+		} else {
+			p, err := findBackground(args[0])
+			if err != nil {
+				return err
+			}
+			processes = append(processes, p)
+		}
+
+		for _, p := range processes {
+			if dryRunFlag {
+				fmt.Printf("Would stop %s (pid %d)\n", p.Name, p.PID)
+				continue
+			}
+			if !p.Running() {
+				p.Forget()
+				fmt.Printf("%s had already exited\n", p.Name)
+				continue
+			}
+
+			if p.Stop(graceFlag) {
+				fmt.Printf("✓ Killed %s (pid %d), it did not exit within %s\n", p.Name, p.PID, graceFlag)
+			} else {
+				fmt.Printf("✓ Stopped %s (pid %d)\n", p.Name, p.PID)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stopCmd)
+}
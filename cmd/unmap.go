@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+var (
+	unmapGlobalFlag bool
+	unmapSharedFlag bool
+)
+
+var unmapCmd = &cobra.Command{
+	Use:   "unmap <command>",
+	Short: "Remove a command mapping for the current project or globally",
+	Long: `Remove a command mapping from the current project or from the
+global commands (with --global) or the project's .tz.json (with --shared).
+
+Examples:
+  tz unmap docker             # Remove the project's docker command
+  tz unmap --global backup    # Remove the global backup command
+  tz unmap --shared test      # Remove the test command from .tz.json
+  tz unmap t                  # Aliases of built-ins work too`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		commandName := builtinName(args[0])
+
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if dryRunFlag {
+			layer := "for this project"
+			if unmapGlobalFlag {
+				layer = "globally"
+			} else if unmapSharedFlag {
+				layer = "from the project's .tz.json"
+			}
+			fmt.Printf("Would remove the mapping '%s' %s\n", commandName, layer)
+			return nil
+		}
+
+		if unmapGlobalFlag {
+			if err := cfg.Update(func(c *config.Config) error {
+				return c.DeleteGlobalCommand(commandName)
+			}); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Removed global mapping '%s'\n", commandName)
+			return nil
+		}
+
+		projectPath, err := currentProject(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
+
+		if unmapSharedFlag {
+			if err := cfg.DeleteSharedCommand(projectPath, commandName); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Removed mapping '%s' from:\n  %s\n", commandName, config.SharedPath(projectPath))
+			return nil
+		}
+
+		if err := cfg.Update(func(c *config.Config) error {
+			return c.DeleteCommand(projectPath, commandName)
+		}); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Removed mapping '%s' for project:\n  %s\n", commandName, projectPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unmapCmd)
+	unmapCmd.Flags().BoolVarP(&unmapGlobalFlag, "global", "g", false, "Remove a global command instead of a project command")
+	unmapCmd.Flags().BoolVarP(&unmapSharedFlag, "shared", "s", false, "Remove the command from the project's .tz.json")
+	unmapCmd.MarkFlagsMutuallyExclusive("global", "shared")
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/pflag"
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/executor"
+	"github.com/totti-rdz/tz/internal/watch"
+)
+
+var (
+	watchFlag      bool
+	watchClearFlag bool
+)
+
+// watchFlags are the flags of commands that support watch mode (test and build)
+var watchFlags = newWatchFlags()
+
+func newWatchFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("watch", pflag.ContinueOnError)
+	fs.BoolVar(&watchFlag, "watch", false, "Rerun the command whenever files in the project change")
+	fs.BoolVar(&watchClearFlag, "clear", false, "Clear the screen before each run (with --watch)")
+	return fs
+}
+
+// watchMapped runs a mapped command and reruns it whenever files under the
+// project change, until tz is interrupted. A run that is still going when
+// files change is stopped first. Dependencies run once, before watching.
+// Every run goes through the cache and is recorded like a normal run.
+func watchMapped(cfg *config.Config, projectPath, commandName, command string, opts executor.Options) error {
+	if err := runDependencies(cfg, projectPath, commandName, nil); err != nil {
+		return err
+	}
+
+	// Keep the terminal (and Ctrl-C) with tz instead of handing it to the
+	// command, so an interrupt ends watching rather than only the current run
+	opts.Stdout = os.Stdout
+	opts.Stderr = os.Stderr
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	quit := make(chan struct{})
+	defer close(quit)
+	changes := watch.New(projectPath, watchedOutputs(cfg, projectPath, commandName, opts)...).Watch(quit)
+
+	var stop chan struct{}
+	var done chan error
+	start := func() {
+		if watchClearFlag {
+			fmt.Print("\033[H\033[2J")
+		}
+		fmt.Printf("▶ %s: %s\n", commandName, command)
+
+		stop = make(chan struct{})
+		done = make(chan error, 1)
+		opts.Stop = stop
+		go func(opts executor.Options) {
+			cached, err := cachedRun(cfg, projectPath, commandName, command, opts, func() error {
+				return execute(cfg, projectPath, commandName, command, typedArgs, "", opts)
+			})
+			if cached {
+				fmt.Printf("✓ %s: cached, inputs unchanged since the last successful run\n", commandName)
+			}
+			done <- err
+		}(opts)
+	}
+
+	start()
+	for {
+		select {
+		case err := <-done:
+			done = nil
+			if err != nil {
+				fmt.Printf("\n✗ %s: %v\n", commandName, err)
+			} else {
+				fmt.Printf("\n✓ %s passed\n", commandName)
+			}
+			fmt.Println("Watching for changes (Ctrl-C to quit)...")
+		case files := <-changes:
+			if done != nil {
+				// Still running: stop it before starting over
+				close(stop)
+				<-done
+			}
+			select {
+			case <-sigs:
+				// Interrupted while the old run was stopping
+				return nil
+			default:
+			}
+			fmt.Printf("\n↻ Changed: %s\n", summarizeFiles(files))
+			start()
+		case <-sigs:
+			// The running command got the signal as well
+			if done != nil {
+				<-done
+			}
+			return nil
+		}
+	}
+}
+
+// watchedOutputs returns the outputs of the mapping relative to the project,
+// so a command that writes its own outputs doesn't trigger itself
+func watchedOutputs(cfg *config.Config, projectPath, commandName string, opts executor.Options) []string {
+	mapping, err := cfg.GetCommand(projectPath, commandName)
+	if err != nil {
+		return nil
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return nil
+		}
+	}
+
+	var outputs []string
+	for _, output := range mapping.Outputs {
+		rel, err := filepath.Rel(projectPath, filepath.Join(dir, filepath.FromSlash(output)))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		outputs = append(outputs, filepath.ToSlash(rel))
+	}
+	return outputs
+}
+
+// summarizeFiles lists a few of the changed files
+func summarizeFiles(files []string) string {
+	const shown = 3
+	if len(files) <= shown {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:shown], ", "), len(files)-shown)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+var whichCmd = &cobra.Command{
+	Use:   "which <command>",
+	Short: "Show which mapping a command resolves to",
+	Long: `Show the shell command a tz command resolves to in the current project
+and which config layer the mapping comes from:
+
+  project   personal mapping in ~/.tz/config.json
+  shared    team mapping in the project's .tz.json
+  global    global mapping in ~/.tz/config.json
+
+Examples:
+  tz which test     # Where does 'tz t' come from?
+  tz which docker   # Where does a custom command come from?`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		commandName := builtinName(args[0])
+
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Get current project root
+		projectPath, err := currentProject(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get current project path: %w", err)
+		}
+
+		command, source, err := cfg.ResolveCommand(projectPath, commandName)
+		if err != nil {
+			return fmt.Errorf("no mapping found for '%s' in this project\n\nTip: Run 'tz map %s \"<your-command>\"' to set it up", commandName, commandName)
+		}
+
+		fmt.Printf("%s -> \"%s\"\n", commandName, command.Run)
+		if command.Description != "" {
+			fmt.Printf("  description: %s\n", command.Description)
+		}
+		fmt.Printf("  from: %s\n", describeSource(cfg, source, projectPath))
+		if command.Cwd != "" {
+			fmt.Printf("  cwd: %s\n", command.Cwd)
+		}
+		for _, pair := range command.EnvList() {
+			fmt.Printf("  env: %s\n", pair)
+		}
+		if command.Shell != "" {
+			fmt.Printf("  shell: %s\n", command.Shell)
+		}
+		if command.Raw {
+			fmt.Printf("  raw: arguments are not quoted\n")
+		}
+		if len(command.Platforms) > 0 {
+			fmt.Printf("  platforms: %s\n", strings.Join(command.Platforms, ", "))
+		}
+		if len(command.DependsOn) > 0 {
+			fmt.Printf("  depends on: %s\n", strings.Join(command.DependsOn, ", "))
+		}
+		if len(command.Inputs) > 0 {
+			fmt.Printf("  inputs: %s\n", strings.Join(command.Inputs, ", "))
+		}
+		if len(command.Outputs) > 0 {
+			fmt.Printf("  outputs: %s\n", strings.Join(command.Outputs, ", "))
+		}
+		if command.Log {
+			fmt.Printf("  log: output of every run is kept in ~/.tz/logs\n")
+		}
+		return nil
+	},
+}
+
+// describeSource explains where a config layer lives on disk
+func describeSource(cfg *config.Config, source config.Source, projectPath string) string {
+	switch source {
+	case config.SourceProject:
+		if entryPath, ok := cfg.ProjectEntry(projectPath); ok && entryPath != projectPath {
+			return fmt.Sprintf("project (~/.tz/config.json, %s, same remote as this checkout)", entryPath)
+		}
+		return fmt.Sprintf("project (~/.tz/config.json, %s)", projectPath)
+	case config.SourceShared:
+		return fmt.Sprintf("shared (%s)", config.SharedPath(projectPath))
+	case config.SourceGlobal:
+		return "global (~/.tz/config.json)"
+	}
+	return string(source)
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+}
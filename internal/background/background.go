@@ -0,0 +1,192 @@
+package background
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/executor"
+)
+
+// Process is a mapped command tz started in the background. Each one is
+// recorded as ~/.tz/run/<project>/<name>.json, next to its <name>.log.
+type Process struct {
+	Name    string    `json:"name"`
+	Project string    `json:"project"`
+	Command string    `json:"command"`
+	PID     int       `json:"pid"`
+	Log     string    `json:"log"`
+	Started time.Time `json:"started"`
+	// When the OS started the process, to notice its PID being reused
+	PIDStart string `json:"pid_start,omitempty"`
+}
+
+// Running reports whether the process (or anything it started) is still
+// alive. A record whose PID now belongs to another process is stale and
+// doesn't count as running.
+func (p *Process) Running() bool {
+	if !executor.GroupAlive(p.PID) {
+		return false
+	}
+	return !p.pidReused()
+}
+
+// pidReused reports whether the recorded PID belongs to a process that
+// started after ours. Once our process has exited, its children keep the
+// process group (and thereby the PID) from being reused, so a group
+// without the process is still ours.
+func (p *Process) pidReused() bool {
+	if p.PIDStart == "" {
+		// Recorded without a start time; nothing to compare
+		return false
+	}
+	start := executor.ProcessStart(p.PID)
+	return start != "" && start != p.PIDStart
+}
+
+// recordPath returns the path of the file the process is recorded in
+func (p *Process) recordPath() string {
+	return filepath.Join(filepath.Dir(p.Log), p.Name+".json")
+}
+
+// Start runs command detached from the terminal with its output going to a
+// log file, and records it under the project's run directory
+func Start(projectPath, name, command string, opts executor.Options) (*Process, error) {
+	existing, err := Find(projectPath, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.Running() {
+			return nil, fmt.Errorf("'%s' is already running (pid %d)\n\nTip: Run 'tz stop %s' first", name, existing.PID, name)
+		}
+		existing.Forget()
+	}
+
+	dir, err := config.ProjectDir("run", projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+
+	// Each run starts a fresh log
+	logPath := filepath.Join(dir, name+".log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+
+	pid, err := executor.StartDetached(command, opts, logFile)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Process{
+		Name:     name,
+		Project:  projectPath,
+		Command:  command,
+		PID:      pid,
+		Log:      logPath,
+		Started:  time.Now(),
+		PIDStart: executor.ProcessStart(pid),
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode process record: %w", err)
+	}
+	if err := os.WriteFile(p.recordPath(), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to record process: %w", err)
+	}
+
+	return p, nil
+}
+
+// List returns the recorded processes of all projects, including ones that
+// have exited since they were started
+func List() ([]*Process, error) {
+	tzDir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(tzDir, "run", "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []*Process
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var p Process
+		if err := json.Unmarshal(data, &p); err != nil {
+			// Skip records we can't make sense of rather than failing
+			continue
+		}
+		processes = append(processes, &p)
+	}
+
+	sort.Slice(processes, func(i, j int) bool {
+		if processes[i].Project != processes[j].Project {
+			return processes[i].Project < processes[j].Project
+		}
+		return processes[i].Name < processes[j].Name
+	})
+
+	return processes, nil
+}
+
+// ForProject returns the recorded processes of one project
+func ForProject(projectPath string) ([]*Process, error) {
+	all, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []*Process
+	for _, p := range all {
+		if p.Project == projectPath {
+			processes = append(processes, p)
+		}
+	}
+	return processes, nil
+}
+
+// Find returns the process recorded under name in the project, or nil
+func Find(projectPath, name string) (*Process, error) {
+	processes, err := ForProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range processes {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// Stop terminates the process and everything it started, then forgets it.
+// It reports whether the process had to be killed after the grace period.
+func (p *Process) Stop(grace time.Duration) bool {
+	killed := executor.StopGroup(p.PID, grace)
+	p.Forget()
+	return killed
+}
+
+// Forget drops the record of a process that has exited. Its log is kept
+// until the command is started again.
+func (p *Process) Forget() {
+	os.Remove(p.recordPath())
+}
@@ -0,0 +1,36 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package background
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/totti-rdz/tz/internal/executor"
+)
+
+func TestRunningNoticesReusedPID(t *testing.T) {
+	// The test's own process group stands in for a background command
+	pgid := syscall.Getpgrp()
+	start := executor.ProcessStart(pgid)
+	if start == "" {
+		t.Skip("the leader of the test's process group has exited")
+	}
+
+	tests := []struct {
+		name     string
+		pidStart string
+		want     bool
+	}{
+		{"same process", start, true},
+		{"recorded without start time", "", true},
+		{"pid reused", "not " + start, false},
+	}
+
+	for _, tt := range tests {
+		p := &Process{Name: "dev", PID: pgid, PIDStart: tt.pidStart}
+		if got := p.Running(); got != tt.want {
+			t.Errorf("%s: Running() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/glob"
+)
+
+// entry records the last successful run of a command. It is stored as
+// ~/.tz/cache/<project>/<name>.json.
+type entry struct {
+	Key  string    `json:"key"`
+	Time time.Time `json:"time"`
+}
+
+// NoMatchError reports an input pattern that matches no files. Its changes
+// could never be noticed, so such a command must not be cached.
+type NoMatchError struct {
+	Pattern string
+}
+
+func (e *NoMatchError) Error() string {
+	return fmt.Sprintf("input pattern %q matches no files", e.Pattern)
+}
+
+// Key hashes everything that decides a command's result: the command
+// itself, where and with which extra environment it runs, and the contents
+// of the files matching the input globs (relative to dir). Every pattern
+// must match at least one file, otherwise Key returns a *NoMatchError.
+func Key(dir, command string, env, inputs []string) (string, error) {
+	for _, pattern := range inputs {
+		files, err := glob.Expand(dir, []string{pattern})
+		if err != nil {
+			return "", err
+		}
+		if len(files) == 0 {
+			return "", &NoMatchError{Pattern: pattern}
+		}
+	}
+
+	files, err := glob.Expand(dir, inputs)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", command, dir)
+	for _, kv := range env {
+		fmt.Fprintf(h, "%s\x00", kv)
+	}
+
+	for _, file := range files {
+		sum, err := fileHash(filepath.Join(dir, file))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00", filepath.ToSlash(file), sum)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Fresh reports whether key is the key of the command's last successful
+// run and all of its outputs (globs relative to dir) still exist. An output
+// directory only has to exist, it may be empty.
+func Fresh(projectPath, name, key, dir string, outputs []string) bool {
+	path, err := entryPath(projectPath, name)
+	if err != nil {
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return false
+	}
+
+	for _, pattern := range outputs {
+		if !glob.Exists(dir, pattern) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Record remembers key as the key of the command's last successful run
+func Record(projectPath, name, key string) error {
+	path, err := entryPath(projectPath, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entry{Key: key, Time: time.Now()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// entryPath returns where the cache entry of a command is stored
+func entryPath(projectPath, name string) (string, error) {
+	dir, err := config.ProjectDir("cache", projectPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// fileHash returns the SHA-256 of a file's contents
+func fileHash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates files (and directories, for names ending in "/")
+// relative to dir
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if name[len(name)-1] == '/' {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []string // Present after the recorded run
+		change  string   // Input file rewritten after the recorded run
+		remove  string   // File or directory removed after the recorded run
+		outputs []string
+		want    bool
+	}{
+		{
+			name:    "unchanged",
+			files:   []string{"src/main.go", "bin/app"},
+			outputs: []string{"bin/app"},
+			want:    true,
+		},
+		{
+			name:   "input changed",
+			files:  []string{"src/main.go"},
+			change: "src/main.go",
+			want:   false,
+		},
+		{
+			name:    "output missing",
+			files:   []string{"src/main.go", "bin/app"},
+			remove:  "bin/app",
+			outputs: []string{"bin/app"},
+			want:    false,
+		},
+		{
+			name:    "empty output directory",
+			files:   []string{"src/main.go", "out/"},
+			outputs: []string{"out"},
+			want:    true,
+		},
+		{
+			name:    "output glob",
+			files:   []string{"src/main.go", "dist/js/app.js"},
+			outputs: []string{"dist/**/*.js"},
+			want:    true,
+		},
+		{
+			name:    "output glob without match",
+			files:   []string{"src/main.go", "dist/"},
+			outputs: []string{"dist/**/*.js"},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files...)
+			inputs := []string{"src/**"}
+
+			key, err := Key(dir, "go build", nil, inputs)
+			if err != nil {
+				t.Fatalf("Key() error = %v", err)
+			}
+			if err := Record(dir, "build", key); err != nil {
+				t.Fatalf("Record() error = %v", err)
+			}
+
+			if tt.change != "" {
+				if err := os.WriteFile(filepath.Join(dir, tt.change), []byte("changed"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.remove != "" {
+				if err := os.RemoveAll(filepath.Join(dir, tt.remove)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			key, err = Key(dir, "go build", nil, inputs)
+			if err != nil {
+				t.Fatalf("Key() error = %v", err)
+			}
+			if got := Fresh(dir, "build", key, dir, tt.outputs); got != tt.want {
+				t.Errorf("Fresh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreshWithoutRecord(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	writeFiles(t, dir, "main.go")
+
+	key, err := Key(dir, "go build", nil, []string{"*.go"})
+	if err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	if Fresh(dir, "build", key, dir, nil) {
+		t.Error("Fresh() = true for a command that never ran")
+	}
+}
+
+func TestKey(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "go.sum", "main.go")
+
+	base, err := Key(dir, "go build", nil, []string{"go.sum", "*.go"})
+	if err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		env     []string
+		inputs  []string
+		same    bool
+	}{
+		{"same", "go build", nil, []string{"go.sum", "*.go"}, true},
+		{"pattern order", "go build", nil, []string{"*.go", "go.sum"}, true},
+		{"other command", "go build -v", nil, []string{"go.sum", "*.go"}, false},
+		{"other env", "go build", []string{"CGO_ENABLED=0"}, []string{"go.sum", "*.go"}, false},
+		{"fewer inputs", "go build", nil, []string{"go.sum"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := Key(dir, tt.command, tt.env, tt.inputs)
+			if err != nil {
+				t.Fatalf("Key() error = %v", err)
+			}
+			if (key == base) != tt.same {
+				t.Errorf("Key() same = %v, want %v", key == base, tt.same)
+			}
+		})
+	}
+}
+
+func TestKeyEmptyInput(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "main.go", "empty/")
+
+	for _, inputs := range [][]string{{"*.rs"}, {"main.go", "src/**"}, {"empty"}} {
+		_, err := Key(dir, "go build", nil, inputs)
+		var noMatch *NoMatchError
+		if !errors.As(err, &noMatch) {
+			t.Errorf("Key(%q) error = %v, want a NoMatchError", inputs, err)
+		}
+	}
+}
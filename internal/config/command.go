@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Command is a command mapping. In JSON it is either a plain string holding
+// the shell command, or an object when more settings are needed:
+//
+//	"dev": "npm run dev"
+//	"dev": {"run": "npm run dev", "cwd": "web", "env": {"PORT": "3000"}}
+type Command struct {
+	Run         string            `json:"run"`
+	Env         map[string]string `json:"env,omitempty"`         // Extra environment variables
+	Cwd         string            `json:"cwd,omitempty"`         // Working directory, relative to the project root
+	Shell       string            `json:"shell,omitempty"`       // Shell that runs the command (default: sh)
+	Description string            `json:"description,omitempty"` // Shown by 'tz list' and 'tz which'
+	Platforms   []string          `json:"platforms,omitempty"`   // Operating systems it runs on (GOOS names)
+	Raw         bool              `json:"raw,omitempty"`         // Pass arguments unquoted so the shell expands them
+	DependsOn   []string          `json:"depends_on,omitempty"`  // Commands that must succeed before this one runs
+	Inputs      []string          `json:"inputs,omitempty"`      // Globs of files the result depends on; unchanged inputs skip the run
+	Outputs     []string          `json:"outputs,omitempty"`     // Globs of files the command produces; missing outputs force a run
+	Log         bool              `json:"log,omitempty"`         // Keep the output of every run in ~/.tz/logs
+}
+
+// commandObject has the same fields as Command without its JSON methods
+type commandObject Command
+
+// IsZero reports whether the mapping is unset
+func (c Command) IsZero() bool {
+	return c.Run == ""
+}
+
+// isPlain reports whether the mapping only has a shell command
+func (c Command) isPlain() bool {
+	return len(c.Env) == 0 && c.Cwd == "" && c.Shell == "" && c.Description == "" && len(c.Platforms) == 0 && !c.Raw && len(c.DependsOn) == 0 && len(c.Inputs) == 0 && len(c.Outputs) == 0 && !c.Log
+}
+
+// MarshalJSON writes plain mappings as a string so the config stays readable
+func (c Command) MarshalJSON() ([]byte, error) {
+	if c.isPlain() {
+		return json.Marshal(c.Run)
+	}
+	return json.Marshal(commandObject(c))
+}
+
+// UnmarshalJSON accepts both the string and the object form
+func (c *Command) UnmarshalJSON(data []byte) error {
+	var run string
+	if err := json.Unmarshal(data, &run); err == nil {
+		*c = Command{Run: run}
+		return nil
+	}
+
+	var obj commandObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fmt.Errorf("command must be a string or an object with a \"run\" field: %w", err)
+	}
+	*c = Command(obj)
+	return nil
+}
+
+// SupportsPlatform reports whether the command may run on the current OS.
+// Commands without a platform list run everywhere.
+func (c Command) SupportsPlatform() bool {
+	if len(c.Platforms) == 0 {
+		return true
+	}
+	for _, platform := range c.Platforms {
+		if strings.EqualFold(platform, runtime.GOOS) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvList returns Env as KEY=VALUE pairs, sorted by key
+func (c Command) EnvList() []string {
+	env := make([]string, 0, len(c.Env))
+	for key, value := range c.Env {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
@@ -1,171 +1,592 @@
 package config
 
 import (
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+
+	"github.com/totti-rdz/tz/internal/filelock"
+	"github.com/totti-rdz/tz/internal/git"
 )
 
 // Config represents the structure of ~/.tz/config.json
 type Config struct {
+	Version  int                      `json:"version"`
+	Global   map[string]Command       `json:"global,omitempty"` // Commands available in every project
 	Projects map[string]ProjectConfig `json:"projects"`
+
+	remotes map[string]string         // Cache of normalized remote URLs by project path
+	shared  map[string]*ProjectConfig // Cache of parsed .tz.json files by project path
 }
 
 // ProjectConfig holds command mappings for a specific project
 type ProjectConfig struct {
-	Install string            `json:"install,omitempty"`
-	Dev     string            `json:"dev,omitempty"`
-	Test    string            `json:"test,omitempty"`
-	Build   string            `json:"build,omitempty"`
-	Clear   string            `json:"clear,omitempty"`
-	Custom  map[string]string `json:"custom,omitempty"` // Custom user-defined commands
+	Remote  string             `json:"remote,omitempty"` // Normalized origin URL, identifies the project across checkouts
+	Install Command            `json:"install,omitzero"`
+	Dev     Command            `json:"dev,omitzero"`
+	Test    Command            `json:"test,omitzero"`
+	Build   Command            `json:"build,omitzero"`
+	Clear   Command            `json:"clear,omitzero"`
+	Custom  map[string]Command `json:"custom,omitempty"` // Custom user-defined commands
 }
 
-// configPath returns the path to the config file
-func configPath() (string, error) {
+// Source identifies the config layer a command mapping was resolved from
+type Source string
+
+const (
+	SourceProject Source = "project" // Personal mapping in ~/.tz/config.json
+	SourceShared  Source = "shared"  // Team mapping in the project's .tz.json
+	SourceGlobal  Source = "global"  // Global mapping in ~/.tz/config.json
+)
+
+// BuiltinCommands lists the commands that have dedicated fields in ProjectConfig
+var BuiltinCommands = []string{"install", "dev", "test", "build", "clear"}
+
+// IsBuiltinCommand reports whether commandName is one of the built-in commands
+func IsBuiltinCommand(commandName string) bool {
+	for _, name := range BuiltinCommands {
+		if name == commandName {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir returns the path to the ~/.tz directory
+func Dir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
-	return filepath.Join(home, ".tz", "config.json"), nil
+	return filepath.Join(home, ".tz"), nil
+}
+
+// ProjectDir returns the directory for per-project state of the given kind
+// under ~/.tz, e.g. ~/.tz/run/<project>. The project's directory name is
+// its base name plus a short hash of its path, so checkouts with the same
+// name don't share state.
+func ProjectDir(kind, projectPath string) (string, error) {
+	tzDir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum([]byte(projectPath))
+	key := fmt.Sprintf("%s-%x", filepath.Base(projectPath), sum[:4])
+	return filepath.Join(tzDir, kind, key), nil
+}
+
+// configPath returns the path to the config file
+func configPath() (string, error) {
+	tzDir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(tzDir, "config.json"), nil
 }
 
 // ensureConfigDir creates the ~/.tz directory if it doesn't exist
 func ensureConfigDir() error {
-	home, err := os.UserHomeDir()
+	tzDir, err := Dir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	tzDir := filepath.Join(home, ".tz")
 	if err := os.MkdirAll(tzDir, 0755); err != nil {
 		return fmt.Errorf("failed to create .tz directory: %w", err)
 	}
 	return nil
 }
 
-// Load reads the config file and returns the Config
+// lock takes the advisory lock on ~/.tz that serializes config writes
+func lock() (*filelock.Lock, error) {
+	if err := ensureConfigDir(); err != nil {
+		return nil, err
+	}
+
+	tzDir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	return filelock.Acquire(filepath.Join(tzDir, ".lock"))
+}
+
+// Load reads the config file and returns the Config. Documents written by
+// older versions of tz are migrated and saved, keeping a .bak backup.
 func Load() (*Config, error) {
-	path, err := configPath()
+	cfg, migrated, err := read()
 	if err != nil {
 		return nil, err
 	}
 
+	if migrated {
+		// Update re-reads under the lock, so only one process migrates
+		if err := cfg.Update(func(*Config) error { return nil }); err != nil {
+			return nil, fmt.Errorf("failed to migrate config: %w", err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// read parses the config file, migrating it in memory. It reports whether
+// the document on disk is out of date.
+func read() (*Config, bool, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, false, err
+	}
+
 	// If config doesn't exist, return empty config
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return &Config{Projects: make(map[string]ProjectConfig)}, nil
+		return &Config{Version: CurrentVersion, Projects: make(map[string]ProjectConfig)}, false, nil
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+		return nil, false, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	migratedData, applied, err := migrateDocument(data)
+	if err != nil {
+		return nil, false, err
 	}
 
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+	if err := json.Unmarshal(migratedData, &cfg); err != nil {
+		return nil, false, fmt.Errorf("failed to parse config: %w", err)
 	}
 
 	if cfg.Projects == nil {
 		cfg.Projects = make(map[string]ProjectConfig)
 	}
 
-	return &cfg, nil
+	return &cfg, len(applied) > 0, nil
 }
 
-// Save writes the config to ~/.tz/config.json
-func (c *Config) Save() error {
-	if err := ensureConfigDir(); err != nil {
+// Update applies a mutation to the config on disk. The config is reloaded
+// under the lock so changes made by other tz processes since Load are kept,
+// and c is refreshed with the result.
+func (c *Config) Update(mutate func(*Config) error) error {
+	l, err := lock()
+	if err != nil {
 		return err
 	}
+	defer l.Release()
+
+	fresh, migrated, err := read()
+	if err != nil {
+		return err
+	}
+	fresh.remotes = c.remotes
+
+	if migrated {
+		if err := backup(); err != nil {
+			return err
+		}
+	}
 
+	if err := mutate(fresh); err != nil {
+		return err
+	}
+
+	if err := fresh.write(); err != nil {
+		return err
+	}
+
+	*c = *fresh
+	return nil
+}
+
+// backup copies the current config file to config.json.bak
+func backup() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	backupPath, err := BackupPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config backup: %w", err)
+	}
+	return nil
+}
+
+// write atomically replaces the config file: the data goes to a temp file
+// in the same directory which is then renamed over config.json, so a crash
+// never leaves a half-written config behind
+func (c *Config) write() error {
 	path, err := configPath()
 	if err != nil {
 		return err
 	}
 
+	c.Version = CurrentVersion
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to serialize config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := writeFileAtomic(path, data); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
 	return nil
 }
 
+// writeFileAtomic writes data to a temp file next to path and renames it
+// into place, so readers see either the old or the new file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // GetCommand retrieves the command mapping for the current project
-func (c *Config) GetCommand(projectPath, commandName string) (string, error) {
-	projectCfg, exists := c.Projects[projectPath]
-	if !exists {
-		return "", fmt.Errorf("no configuration found for project: %s", projectPath)
+func (c *Config) GetCommand(projectPath, commandName string) (Command, error) {
+	cmd, _, err := c.ResolveCommand(projectPath, commandName)
+	return cmd, err
+}
+
+// ResolveCommand looks up a command and reports which layer it came from.
+// Personal project mappings win over the team's .tz.json, which wins over
+// global ones; built-in commands can't be global.
+func (c *Config) ResolveCommand(projectPath, commandName string) (Command, Source, error) {
+	projectCfg, exists := c.lookupProject(projectPath)
+	if cmd := projectCfg.lookup(commandName); !cmd.IsZero() {
+		return cmd, SourceProject, nil
 	}
 
-	var cmd string
-	switch commandName {
-	case "install":
-		cmd = projectCfg.Install
-	case "dev":
-		cmd = projectCfg.Dev
-	case "test":
-		cmd = projectCfg.Test
-	case "build":
-		cmd = projectCfg.Build
-	case "clear":
-		cmd = projectCfg.Clear
-	default:
-		// Check custom commands
-		if projectCfg.Custom != nil {
-			if customCmd, ok := projectCfg.Custom[commandName]; ok {
-				cmd = customCmd
-			}
-		}
-		if cmd == "" {
-			return "", fmt.Errorf("unknown command: %s", commandName)
+	sharedCfg, err := c.sharedProject(projectPath)
+	if err != nil {
+		return Command{}, "", err
+	}
+	if cmd := sharedCfg.lookup(commandName); !cmd.IsZero() {
+		return cmd, SourceShared, nil
+	}
+
+	if IsBuiltinCommand(commandName) {
+		if !exists && sharedCfg == nil {
+			return Command{}, "", fmt.Errorf("no configuration found for project: %s", projectPath)
 		}
+		return Command{}, "", fmt.Errorf("no mapping found for '%s' in project: %s", commandName, projectPath)
 	}
 
-	if cmd == "" {
-		return "", fmt.Errorf("no mapping found for '%s' in project: %s", commandName, projectPath)
+	// Fall back to global commands
+	if globalCmd := c.Global[commandName]; !globalCmd.IsZero() {
+		return globalCmd, SourceGlobal, nil
 	}
 
-	return cmd, nil
+	return Command{}, "", fmt.Errorf("unknown command: %s", commandName)
 }
 
-// SetCommand sets a command mapping for a project
-func (c *Config) SetCommand(projectPath, commandName, command string) error {
+// lookupProject returns the config for a project by path, falling back to
+// a project with the same origin remote (a moved or re-cloned checkout)
+func (c *Config) lookupProject(projectPath string) (ProjectConfig, bool) {
+	entryPath, ok := c.ProjectEntry(projectPath)
+	if !ok {
+		return ProjectConfig{}, false
+	}
+	return c.Projects[entryPath], true
+}
+
+// ProjectEntry returns the path of the config entry that holds the
+// project's personal mappings: its own, or that of another checkout with
+// the same origin remote
+func (c *Config) ProjectEntry(projectPath string) (string, bool) {
+	if _, exists := c.Projects[projectPath]; exists {
+		return projectPath, true
+	}
+	return c.FindProjectByRemote(c.ProjectRemote(projectPath), projectPath)
+}
+
+// ownProject makes sure the project has an entry of its own before it is
+// changed. A checkout that inherits mappings through its remote starts
+// with a copy of them, so the change neither touches the other checkout
+// nor hides the inherited mappings.
+func (c *Config) ownProject(projectPath string) {
 	if c.Projects == nil {
 		c.Projects = make(map[string]ProjectConfig)
 	}
+	if entryPath, ok := c.ProjectEntry(projectPath); ok && entryPath != projectPath {
+		c.Projects[projectPath] = c.Projects[entryPath].clone()
+	}
+}
+
+// ProjectRemote returns the normalized origin remote URL of the project at
+// projectPath, or an empty string if it has none
+func (c *Config) ProjectRemote(projectPath string) string {
+	if remote, ok := c.remotes[projectPath]; ok {
+		return remote
+	}
+
+	url, err := git.RemoteURL(projectPath)
+	remote := ""
+	if err == nil {
+		remote = git.NormalizeRemote(url)
+	}
+
+	if c.remotes == nil {
+		c.remotes = make(map[string]string)
+	}
+	c.remotes[projectPath] = remote
+	return remote
+}
+
+// FindProjectByRemote returns the path of a configured project with the
+// given remote, ignoring the project at exclude
+func (c *Config) FindProjectByRemote(remote, exclude string) (string, bool) {
+	if remote == "" {
+		return "", false
+	}
+
+	// Sort paths so the match is stable when several checkouts share a remote
+	paths := make([]string, 0, len(c.Projects))
+	for path := range c.Projects {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if path != exclude && c.Projects[path].Remote == remote {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// AdoptProject copies the mappings of the project at fromPath to toPath.
+// If fromPath no longer exists on disk the checkout was moved, so the old
+// entry is dropped.
+func (c *Config) AdoptProject(fromPath, toPath string) {
+	c.Projects[toPath] = c.Projects[fromPath].clone()
+
+	if _, err := os.Stat(fromPath); os.IsNotExist(err) {
+		delete(c.Projects, fromPath)
+	}
+}
+
+// clone returns a copy of the project config that doesn't share its custom
+// map with p
+func (p ProjectConfig) clone() ProjectConfig {
+	if p.Custom != nil {
+		custom := make(map[string]Command, len(p.Custom))
+		for name, cmd := range p.Custom {
+			custom[name] = cmd
+		}
+		p.Custom = custom
+	}
+	return p
+}
+
+// lookup returns the mapping for a built-in or custom command, if any
+func (p *ProjectConfig) lookup(commandName string) Command {
+	if p == nil {
+		return Command{}
+	}
+	if IsBuiltinCommand(commandName) {
+		return p.builtin(commandName)
+	}
+	return p.Custom[commandName]
+}
+
+// builtin returns the mapping stored in the field for a built-in command
+func (p ProjectConfig) builtin(commandName string) Command {
+	switch commandName {
+	case "install":
+		return p.Install
+	case "dev":
+		return p.Dev
+	case "test":
+		return p.Test
+	case "build":
+		return p.Build
+	case "clear":
+		return p.Clear
+	}
+	return Command{}
+}
+
+// SetCommand sets a command mapping for a project
+func (c *Config) SetCommand(projectPath, commandName string, command Command) error {
+	c.ownProject(projectPath)
 
 	projectCfg := c.Projects[projectPath]
+	if projectCfg.Remote == "" {
+		projectCfg.Remote = c.ProjectRemote(projectPath)
+	}
+
+	projectCfg.set(commandName, command)
+	c.Projects[projectPath] = projectCfg
+	return nil
+}
 
+// set stores a mapping in the field for a built-in command or in Custom
+func (p *ProjectConfig) set(commandName string, command Command) {
 	switch commandName {
 	case "install":
-		projectCfg.Install = command
+		p.Install = command
 	case "dev":
-		projectCfg.Dev = command
+		p.Dev = command
 	case "test":
-		projectCfg.Test = command
+		p.Test = command
 	case "build":
-		projectCfg.Build = command
+		p.Build = command
 	case "clear":
-		projectCfg.Clear = command
+		p.Clear = command
 	default:
 		// Custom command
-		if projectCfg.Custom == nil {
-			projectCfg.Custom = make(map[string]string)
+		if p.Custom == nil {
+			p.Custom = make(map[string]Command)
 		}
-		projectCfg.Custom[commandName] = command
+		p.Custom[commandName] = command
+	}
+}
+
+// remove deletes a mapping and reports whether there was one
+func (p *ProjectConfig) remove(commandName string) bool {
+	if p.lookup(commandName).IsZero() {
+		return false
+	}
+
+	if IsBuiltinCommand(commandName) {
+		// A zero Command clears the field (omitzero drops it)
+		p.set(commandName, Command{})
+		return true
+	}
+
+	delete(p.Custom, commandName)
+	if len(p.Custom) == 0 {
+		p.Custom = nil
+	}
+	return true
+}
+
+// DeleteCommand removes a command mapping from a project
+func (c *Config) DeleteCommand(projectPath, commandName string) error {
+	if _, exists := c.ProjectEntry(projectPath); !exists {
+		return fmt.Errorf("no configuration found for project: %s", projectPath)
+	}
+	c.ownProject(projectPath)
+	projectCfg := c.Projects[projectPath]
+
+	if !projectCfg.remove(commandName) {
+		return fmt.Errorf("no mapping found for '%s' in project: %s", commandName, projectPath)
 	}
 
 	c.Projects[projectPath] = projectCfg
 	return nil
 }
 
+// GetGlobalCommand retrieves a global command mapping
+func (c *Config) GetGlobalCommand(commandName string) (Command, error) {
+	cmd, ok := c.Global[commandName]
+	if !ok || cmd.IsZero() {
+		return Command{}, fmt.Errorf("no global mapping found for '%s'", commandName)
+	}
+	return cmd, nil
+}
+
+// SetGlobalCommand sets a command mapping that is available in every project
+func (c *Config) SetGlobalCommand(commandName string, command Command) error {
+	if IsBuiltinCommand(commandName) {
+		return fmt.Errorf("'%s' is a built-in command and can only be mapped per-project", commandName)
+	}
+
+	if c.Global == nil {
+		c.Global = make(map[string]Command)
+	}
+	c.Global[commandName] = command
+	return nil
+}
+
+// DeleteGlobalCommand removes a global command mapping
+func (c *Config) DeleteGlobalCommand(commandName string) error {
+	if _, ok := c.Global[commandName]; !ok {
+		return fmt.Errorf("no global mapping found for '%s'", commandName)
+	}
+	delete(c.Global, commandName)
+	return nil
+}
+
+// ResolvedCommand is a command mapping together with the layer it came from
+type ResolvedCommand struct {
+	Name    string
+	Command Command
+	Source  Source
+}
+
+// ListCommands returns every command available in a project, sorted by name.
+// Mappings that are overridden by a higher-priority layer are left out.
+func (c *Config) ListCommands(projectPath string) []ResolvedCommand {
+	names := make(map[string]bool)
+	projectCfg, _ := c.lookupProject(projectPath)
+	sharedCfg, _ := c.sharedProject(projectPath)
+	for _, p := range []*ProjectConfig{&projectCfg, sharedCfg} {
+		if p == nil {
+			continue
+		}
+		for _, name := range BuiltinCommands {
+			if !p.builtin(name).IsZero() {
+				names[name] = true
+			}
+		}
+		for name := range p.Custom {
+			names[name] = true
+		}
+	}
+	for name := range c.Global {
+		names[name] = true
+	}
+
+	var resolved []ResolvedCommand
+	for name := range names {
+		cmd, source, err := c.ResolveCommand(projectPath, name)
+		if err != nil {
+			continue
+		}
+		resolved = append(resolved, ResolvedCommand{Name: name, Command: cmd, Source: source})
+	}
+
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].Name < resolved[j].Name
+	})
+	return resolved
+}
+
 // GetCurrentProjectPath returns the absolute path of the current working directory
 func GetCurrentProjectPath() (string, error) {
 	path, err := os.Getwd()
@@ -174,3 +595,39 @@ func GetCurrentProjectPath() (string, error) {
 	}
 	return path, nil
 }
+
+// CurrentProjectPath returns the root of the project containing the
+// current working directory (see FindProjectRoot)
+func (c *Config) CurrentProjectPath() (string, error) {
+	cwd, err := GetCurrentProjectPath()
+	if err != nil {
+		return "", err
+	}
+	return c.FindProjectRoot(cwd), nil
+}
+
+// FindProjectRoot walks up from dir to the nearest configured project (or
+// one with a .tz.json).
+// If none is found, the git toplevel is used, and dir itself as a last resort.
+func (c *Config) FindProjectRoot(dir string) string {
+	for current := filepath.Clean(dir); ; {
+		if _, exists := c.Projects[current]; exists {
+			return current
+		}
+		if _, err := os.Stat(filepath.Join(current, SharedFile)); err == nil {
+			return current
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+
+	if root, err := git.TopLevel(dir); err == nil && root != "" {
+		return filepath.Clean(root)
+	}
+
+	return dir
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// clones returns a config with a project at /old whose checkout at /new
+// has the same origin remote
+func clones() *Config {
+	return &Config{
+		Projects: map[string]ProjectConfig{
+			"/old": {
+				Remote: "github.com/User/repo",
+				Test:   Command{Run: "go test ./..."},
+				Custom: map[string]Command{"lint": {Run: "golangci-lint run"}},
+			},
+		},
+		remotes: map[string]string{"/old": "github.com/User/repo", "/new": "github.com/User/repo"},
+	}
+}
+
+func TestProjectEntry(t *testing.T) {
+	c := clones()
+	c.remotes["/other"] = "github.com/User/other"
+
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"/old", "/old", true},
+		{"/new", "/old", true},
+		{"/other", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := c.ProjectEntry(tt.path)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ProjectEntry(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSetCommandKeepsInheritedMappings(t *testing.T) {
+	c := clones()
+
+	if err := c.SetCommand("/new", "build", Command{Run: "go build"}); err != nil {
+		t.Fatalf("SetCommand() error = %v", err)
+	}
+
+	for _, name := range []string{"test", "lint", "build"} {
+		if _, _, err := c.ResolveCommand("/new", name); err != nil {
+			t.Errorf("ResolveCommand(/new, %s) error = %v", name, err)
+		}
+	}
+	if _, _, err := c.ResolveCommand("/old", "build"); err == nil {
+		t.Error("SetCommand() on /new changed /old")
+	}
+}
+
+func TestDeleteCommandInheritedMapping(t *testing.T) {
+	c := clones()
+
+	if err := c.DeleteCommand("/new", "lint"); err != nil {
+		t.Fatalf("DeleteCommand() error = %v", err)
+	}
+
+	if _, _, err := c.ResolveCommand("/new", "lint"); err == nil {
+		t.Error("ResolveCommand(/new, lint) still finds the deleted mapping")
+	}
+	if _, _, err := c.ResolveCommand("/new", "test"); err != nil {
+		t.Errorf("ResolveCommand(/new, test) error = %v", err)
+	}
+	if want := map[string]Command{"lint": {Run: "golangci-lint run"}}; !reflect.DeepEqual(c.Projects["/old"].Custom, want) {
+		t.Errorf("DeleteCommand() on /new changed /old: %v", c.Projects["/old"].Custom)
+	}
+}
+
+func TestResolveCommandLayers(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	projectPath := t.TempDir()
+
+	personal := `{
+  "version": 1,
+  "global": {"deploy": "global deploy", "lint": "global lint", "fmt": "global fmt"},
+  "projects": {
+    "` + projectPath + `": {
+      "test": "personal test",
+      "dev": "",
+      "custom": {"fmt": "personal fmt", "empty": ""}
+    }
+  }
+}`
+	shared := `{
+  "test": "shared test",
+  "build": "shared build",
+  "dev": "shared dev",
+  "custom": {"lint": "shared lint", "empty": "shared empty"}
+}`
+	if err := os.MkdirAll(filepath.Join(home, ".tz"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".tz", "config.json"), []byte(personal), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(SharedPath(projectPath), []byte(shared), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		command    string
+		wantRun    string
+		wantSource Source
+		wantErr    bool
+	}{
+		{command: "test", wantRun: "personal test", wantSource: SourceProject},
+		{command: "build", wantRun: "shared build", wantSource: SourceShared},
+		{command: "dev", wantRun: "shared dev", wantSource: SourceShared},
+		{command: "fmt", wantRun: "personal fmt", wantSource: SourceProject},
+		{command: "lint", wantRun: "shared lint", wantSource: SourceShared},
+		{command: "empty", wantRun: "shared empty", wantSource: SourceShared},
+		{command: "deploy", wantRun: "global deploy", wantSource: SourceGlobal},
+		{command: "clear", wantErr: true},
+		{command: "missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			cmd, source, err := c.ResolveCommand(projectPath, tt.command)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResolveCommand() = %q from %s, want an error", cmd.Run, source)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveCommand() error = %v", err)
+			}
+			if cmd.Run != tt.wantRun || source != tt.wantSource {
+				t.Errorf("ResolveCommand() = %q from %s, want %q from %s", cmd.Run, source, tt.wantRun, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestResolveCommandGlobalOutsideProjects(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := &Config{Global: map[string]Command{"backup": {Run: "restic backup"}}}
+	projectPath := t.TempDir()
+
+	if cmd, source, err := c.ResolveCommand(projectPath, "backup"); err != nil || cmd.Run != "restic backup" || source != SourceGlobal {
+		t.Errorf("ResolveCommand(backup) = %q, %s, %v, want the global command", cmd.Run, source, err)
+	}
+	if _, _, err := c.ResolveCommand(projectPath, "test"); err == nil {
+		t.Error("ResolveCommand(test) succeeded without any mapping")
+	}
+}
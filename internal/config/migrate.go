@@ -0,0 +1,163 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CurrentVersion is the config schema version written by this build of tz
+const CurrentVersion = 1
+
+// migration upgrades a raw config document from version from to from+1
+type migration struct {
+	from        int
+	description string
+	apply       func(doc map[string]any) error
+}
+
+// migrations are applied in order to bring older documents up to date.
+// Each one works on the raw JSON so it doesn't depend on the current structs.
+var migrations = []migration{
+	{
+		from:        0,
+		description: "add schema version and drop empty command mappings",
+		apply:       migrateV0ToV1,
+	},
+}
+
+// migrateV0ToV1 removes empty strings and empty custom maps left behind by
+// hand-editing, which the unversioned format silently tolerated
+func migrateV0ToV1(doc map[string]any) error {
+	projects, _ := doc["projects"].(map[string]any)
+	for _, p := range projects {
+		project, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for key, value := range project {
+			if value == "" {
+				delete(project, key)
+			}
+		}
+
+		if custom, ok := project["custom"].(map[string]any); ok {
+			for name, value := range custom {
+				if value == "" {
+					delete(custom, name)
+				}
+			}
+			if len(custom) == 0 {
+				delete(project, "custom")
+			}
+		}
+	}
+
+	if global, ok := doc["global"].(map[string]any); ok {
+		for name, value := range global {
+			if value == "" {
+				delete(global, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// documentVersion returns the schema version of a raw config document.
+// Documents from before versioning have no version field and count as 0.
+func documentVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["version"]
+	if !ok {
+		return 0, nil
+	}
+	version, ok := raw.(float64)
+	if !ok || version != float64(int(version)) || version < 0 {
+		return 0, fmt.Errorf("invalid config version: %v", raw)
+	}
+	return int(version), nil
+}
+
+// migrateDocument upgrades data to CurrentVersion and returns the new
+// document along with the descriptions of the migrations that were applied
+func migrateDocument(data []byte) ([]byte, []string, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version > CurrentVersion {
+		return nil, nil, fmt.Errorf("config version %d is newer than this tz supports (%d), please upgrade tz", version, CurrentVersion)
+	}
+	if version == CurrentVersion {
+		return data, nil, nil
+	}
+
+	var applied []string
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		if err := m.apply(doc); err != nil {
+			return nil, nil, fmt.Errorf("failed to migrate config from version %d: %w", m.from, err)
+		}
+		applied = append(applied, fmt.Sprintf("v%d -> v%d: %s", m.from, m.from+1, m.description))
+	}
+	doc["version"] = CurrentVersion
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize config: %w", err)
+	}
+	return migrated, applied, nil
+}
+
+// PendingMigrations reports the migrations that Load would apply to the
+// config on disk, without changing anything
+func PendingMigrations() ([]string, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	_, applied, err := migrateDocument(data)
+	return applied, err
+}
+
+// Migrate upgrades the config on disk to CurrentVersion, keeping a backup of
+// the old document, and returns the migrations that were applied
+func Migrate() ([]string, error) {
+	applied, err := PendingMigrations()
+	if err != nil || len(applied) == 0 {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	// Update re-reads and migrates under the lock, which writes the backup
+	if err := cfg.Update(func(*Config) error { return nil }); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// BackupPath returns the path where the pre-migration config is kept
+func BackupPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return path + ".bak", nil
+}
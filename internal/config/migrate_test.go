@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMigrateDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		want        string // Expected document, compared as JSON
+		wantApplied int
+		wantErr     bool
+	}{
+		{
+			name:        "v0 drops empty mappings",
+			input:       `{"projects":{"/p":{"install":"npm install","dev":"","custom":{"seed":"","lint":"eslint ."}}},"global":{"hi":"","yo":"echo yo"}}`,
+			want:        `{"version":1,"projects":{"/p":{"install":"npm install","custom":{"lint":"eslint ."}}},"global":{"yo":"echo yo"}}`,
+			wantApplied: 1,
+		},
+		{
+			name:        "v0 drops empty custom map",
+			input:       `{"projects":{"/p":{"test":"go test","custom":{"x":""}}}}`,
+			want:        `{"version":1,"projects":{"/p":{"test":"go test"}}}`,
+			wantApplied: 1,
+		},
+		{
+			name:        "v0 keeps command objects",
+			input:       `{"projects":{"/p":{"dev":{"run":"npm run dev","cwd":"web"}}}}`,
+			want:        `{"version":1,"projects":{"/p":{"dev":{"run":"npm run dev","cwd":"web"}}}}`,
+			wantApplied: 1,
+		},
+		{
+			name:        "v0 empty document",
+			input:       `{}`,
+			want:        `{"version":1}`,
+			wantApplied: 1,
+		},
+		{
+			name:  "current version unchanged",
+			input: `{"version":1,"projects":{"/p":{"dev":""}}}`,
+			want:  `{"version":1,"projects":{"/p":{"dev":""}}}`,
+		},
+		{
+			name:    "newer version",
+			input:   `{"version":2}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid version",
+			input:   `{"version":"1"}`,
+			wantErr: true,
+		},
+		{
+			name:    "fractional version",
+			input:   `{"version":0.5}`,
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			input:   `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, applied, err := migrateDocument([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("migrateDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(applied) != tt.wantApplied {
+				t.Errorf("migrateDocument() applied %q, want %d migrations", applied, tt.wantApplied)
+			}
+
+			var gotDoc, wantDoc any
+			if err := json.Unmarshal(got, &gotDoc); err != nil {
+				t.Fatalf("migrateDocument() returned invalid JSON: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantDoc); err != nil {
+				t.Fatalf("invalid expected JSON: %v", err)
+			}
+			if !reflect.DeepEqual(gotDoc, wantDoc) {
+				t.Errorf("migrateDocument() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentVersion(t *testing.T) {
+	tests := []struct {
+		doc     map[string]any
+		want    int
+		wantErr bool
+	}{
+		{map[string]any{}, 0, false},
+		{map[string]any{"version": float64(0)}, 0, false},
+		{map[string]any{"version": float64(3)}, 3, false},
+		{map[string]any{"version": float64(-1)}, 0, true},
+		{map[string]any{"version": float64(1.5)}, 0, true},
+		{map[string]any{"version": "1"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := documentVersion(tt.doc)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("documentVersion(%v) error = %v, wantErr %v", tt.doc, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("documentVersion(%v) = %d, want %d", tt.doc, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SharedFile is the optional team config committed to a project's root.
+// It uses the same format as a project entry in ~/.tz/config.json and sits
+// beneath personal mappings.
+const SharedFile = ".tz.json"
+
+// SharedPath returns the path to the team config of a project
+func SharedPath(projectPath string) string {
+	return filepath.Join(projectPath, SharedFile)
+}
+
+// sharedProject returns the parsed team config of a project, or nil if the
+// project has none
+func (c *Config) sharedProject(projectPath string) (*ProjectConfig, error) {
+	if projectCfg, ok := c.shared[projectPath]; ok {
+		return projectCfg, nil
+	}
+
+	projectCfg, err := readShared(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.shared == nil {
+		c.shared = make(map[string]*ProjectConfig)
+	}
+	c.shared[projectPath] = projectCfg
+	return projectCfg, nil
+}
+
+// readShared parses a project's .tz.json, returning nil if it doesn't exist
+func readShared(projectPath string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(SharedPath(projectPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SharedFile, err)
+	}
+
+	var projectCfg ProjectConfig
+	if err := json.Unmarshal(data, &projectCfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SharedPath(projectPath), err)
+	}
+	return &projectCfg, nil
+}
+
+// writeShared saves a project's .tz.json and refreshes the cached copy
+func (c *Config) writeShared(projectPath string, projectCfg *ProjectConfig) error {
+	data, err := json.MarshalIndent(projectCfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize %s: %w", SharedFile, err)
+	}
+
+	// Keep a trailing newline, the file is meant to be committed
+	if err := writeFileAtomic(SharedPath(projectPath), append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SharedFile, err)
+	}
+
+	if c.shared == nil {
+		c.shared = make(map[string]*ProjectConfig)
+	}
+	c.shared[projectPath] = projectCfg
+	return nil
+}
+
+// SetSharedCommand sets a command mapping in the project's .tz.json
+func (c *Config) SetSharedCommand(projectPath, commandName string, command Command) error {
+	projectCfg, err := readShared(projectPath)
+	if err != nil {
+		return err
+	}
+	if projectCfg == nil {
+		projectCfg = &ProjectConfig{}
+	}
+
+	projectCfg.set(commandName, command)
+	return c.writeShared(projectPath, projectCfg)
+}
+
+// DeleteSharedCommand removes a command mapping from the project's .tz.json
+func (c *Config) DeleteSharedCommand(projectPath, commandName string) error {
+	projectCfg, err := readShared(projectPath)
+	if err != nil {
+		return err
+	}
+	if projectCfg == nil || !projectCfg.remove(commandName) {
+		return fmt.Errorf("no mapping found for '%s' in %s", commandName, SharedPath(projectPath))
+	}
+
+	return c.writeShared(projectPath, projectCfg)
+}
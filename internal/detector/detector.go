@@ -14,47 +14,49 @@ const (
 	Python  ProjectType = "Python"
 	Rust    ProjectType = "Rust"
 	Ruby    ProjectType = "Ruby"
-	Java    ProjectType = "Java"
+	Maven   ProjectType = "Maven"
+	Gradle  ProjectType = "Gradle"
+	Just    ProjectType = "just"
+	Task    ProjectType = "Task"
+	Make    ProjectType = "Make"
 	Unknown ProjectType = "Unknown"
 )
 
+// markers lists the files that identify each project type, in the order
+// they are checked
+var markers = []struct {
+	projectType ProjectType
+	files       []string
+}{
+	{NodeJS, []string{"package.json"}},
+	{Go, []string{"go.mod"}},
+	{Python, []string{"pyproject.toml", "requirements.txt", "setup.py", "Pipfile"}},
+	{Rust, []string{"Cargo.toml"}},
+	{Ruby, []string{"Gemfile"}},
+	{Maven, []string{"pom.xml"}},
+	{Gradle, []string{"build.gradle.kts", "build.gradle", "settings.gradle.kts", "settings.gradle"}},
+	// Task runners only decide the type when no language is detected
+	{Just, []string{"justfile", "Justfile", ".justfile"}},
+	{Task, []string{"Taskfile.yml", "Taskfile.yaml", "taskfile.yml", "taskfile.yaml"}},
+	{Make, []string{"GNUmakefile", "makefile", "Makefile"}},
+}
+
 // DetectProjectType detects the project type based on marker files in the directory
 func DetectProjectType(projectPath string) ProjectType {
-	// Check for Node.js
-	if fileExists(filepath.Join(projectPath, "package.json")) {
-		return NodeJS
-	}
-
-	// Check for Go
-	if fileExists(filepath.Join(projectPath, "go.mod")) {
-		return Go
-	}
-
-	// Check for Python
-	if fileExists(filepath.Join(projectPath, "pyproject.toml")) ||
-		fileExists(filepath.Join(projectPath, "requirements.txt")) ||
-		fileExists(filepath.Join(projectPath, "setup.py")) {
-		return Python
-	}
-
-	// Check for Rust
-	if fileExists(filepath.Join(projectPath, "Cargo.toml")) {
-		return Rust
-	}
-
-	// Check for Ruby
-	if fileExists(filepath.Join(projectPath, "Gemfile")) {
-		return Ruby
-	}
+	projectType, _ := Detect(projectPath)
+	return projectType
+}
 
-	// Check for Java
-	if fileExists(filepath.Join(projectPath, "pom.xml")) ||
-		fileExists(filepath.Join(projectPath, "build.gradle")) ||
-		fileExists(filepath.Join(projectPath, "build.gradle.kts")) {
-		return Java
+// Detect returns the project type and the marker file it was detected by
+func Detect(projectPath string) (ProjectType, string) {
+	for _, m := range markers {
+		for _, file := range m.files {
+			if fileExists(filepath.Join(projectPath, file)) {
+				return m.projectType, file
+			}
+		}
 	}
-
-	return Unknown
+	return Unknown, ""
 }
 
 // fileExists checks if a file exists
@@ -0,0 +1,91 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gradleBuildFiles are the Gradle files whose contents tell which plugins
+// a project uses. app/ is where Android Studio puts the application module.
+var gradleBuildFiles = []string{
+	"build.gradle", "build.gradle.kts",
+	"settings.gradle", "settings.gradle.kts",
+	"app/build.gradle", "app/build.gradle.kts",
+}
+
+// mavenSuggestions returns the suggested commands for a Maven project,
+// run through ./mvnw when the project has the wrapper
+func mavenSuggestions(projectPath string) *CommandSuggestions {
+	mvn := "mvn"
+	if fileExists(filepath.Join(projectPath, "mvnw")) {
+		mvn = "./mvnw"
+	}
+
+	pom := readFiles(projectPath, "pom.xml")
+	dev := mvn + " exec:java"
+	switch {
+	case strings.Contains(pom, "spring-boot"):
+		dev = mvn + " spring-boot:run"
+	case strings.Contains(pom, "quarkus-maven-plugin"):
+		dev = mvn + " quarkus:dev"
+	}
+
+	return &CommandSuggestions{
+		Install: mvn + " install -DskipTests",
+		Dev:     dev,
+		Test:    mvn + " test",
+		Build:   mvn + " package",
+		Clear:   mvn + " clean",
+	}
+}
+
+// gradleSuggestions returns the suggested commands for a Gradle project,
+// run through ./gradlew when the project has the wrapper. Android and
+// Kotlin Multiplatform projects get their own conventional tasks.
+func gradleSuggestions(projectPath string) *CommandSuggestions {
+	gradle := "gradle"
+	if fileExists(filepath.Join(projectPath, "gradlew")) {
+		gradle = "./gradlew"
+	}
+
+	build := readFiles(projectPath, gradleBuildFiles...)
+	suggestions := &CommandSuggestions{
+		Install: gradle + " dependencies",
+		Dev:     gradle + " run",
+		Test:    gradle + " test",
+		Build:   gradle + " build",
+		Clear:   gradle + " clean",
+	}
+
+	switch {
+	case strings.Contains(build, "com.android.application") || strings.Contains(build, "com.android.library"):
+		suggestions.Dev = gradle + " installDebug"
+		suggestions.Test = gradle + " testDebugUnitTest"
+		suggestions.Build = gradle + " assembleDebug"
+	case strings.Contains(build, `kotlin("multiplatform")`) || strings.Contains(build, "org.jetbrains.kotlin.multiplatform"):
+		// There is no single run task across targets
+		suggestions.Dev = ""
+		suggestions.Test = gradle + " allTests"
+		suggestions.Build = gradle + " assemble"
+	case strings.Contains(build, "org.springframework.boot"):
+		suggestions.Dev = gradle + " bootRun"
+	case strings.Contains(build, "io.quarkus"):
+		suggestions.Dev = gradle + " quarkusDev"
+	}
+
+	return suggestions
+}
+
+// readFiles returns the contents of the files that exist, joined
+func readFiles(projectPath string, names ...string) string {
+	var b strings.Builder
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(projectPath, name))
+		if err == nil {
+			b.Write(data)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,181 @@
+package detector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMavenAndGradleDetected(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  ProjectType
+	}{
+		{"pom.xml", map[string]string{"pom.xml": "<project/>"}, Maven},
+		{"build.gradle", map[string]string{"build.gradle": ""}, Gradle},
+		{"build.gradle.kts", map[string]string{"build.gradle.kts": ""}, Gradle},
+		{"settings.gradle.kts only", map[string]string{"settings.gradle.kts": ""}, Gradle},
+		{"Maven before Gradle", map[string]string{"pom.xml": "<project/>", "build.gradle": ""}, Maven},
+	}
+
+	for _, tt := range tests {
+		if got := DetectProjectType(writeProject(t, tt.files)); got != tt.want {
+			t.Errorf("%s: DetectProjectType() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMavenSuggestions(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  CommandSuggestions
+	}{
+		{
+			name:  "mvn",
+			files: map[string]string{"pom.xml": "<project/>"},
+			want: CommandSuggestions{
+				Install: "mvn install -DskipTests",
+				Dev:     "mvn exec:java",
+				Test:    "mvn test",
+				Build:   "mvn package",
+				Clear:   "mvn clean",
+			},
+		},
+		{
+			name:  "mvnw wrapper",
+			files: map[string]string{"pom.xml": "<project/>", "mvnw": "#!/bin/sh\n"},
+			want: CommandSuggestions{
+				Install: "./mvnw install -DskipTests",
+				Dev:     "./mvnw exec:java",
+				Test:    "./mvnw test",
+				Build:   "./mvnw package",
+				Clear:   "./mvnw clean",
+			},
+		},
+		{
+			name:  "Spring Boot",
+			files: map[string]string{"pom.xml": "<artifactId>spring-boot-starter-parent</artifactId>", "mvnw": ""},
+			want: CommandSuggestions{
+				Install: "./mvnw install -DskipTests",
+				Dev:     "./mvnw spring-boot:run",
+				Test:    "./mvnw test",
+				Build:   "./mvnw package",
+				Clear:   "./mvnw clean",
+			},
+		},
+		{
+			name:  "Quarkus",
+			files: map[string]string{"pom.xml": "<artifactId>quarkus-maven-plugin</artifactId>"},
+			want: CommandSuggestions{
+				Install: "mvn install -DskipTests",
+				Dev:     "mvn quarkus:dev",
+				Test:    "mvn test",
+				Build:   "mvn package",
+				Clear:   "mvn clean",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, projectType := SuggestProjectCommands(writeProject(t, tt.files))
+			if projectType != Maven {
+				t.Fatalf("SuggestProjectCommands() type = %s, want %s", projectType, Maven)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("SuggestProjectCommands() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGradleSuggestions(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  CommandSuggestions
+	}{
+		{
+			name:  "gradle",
+			files: map[string]string{"build.gradle": "plugins { id 'application' }"},
+			want: CommandSuggestions{
+				Install: "gradle dependencies",
+				Dev:     "gradle run",
+				Test:    "gradle test",
+				Build:   "gradle build",
+				Clear:   "gradle clean",
+			},
+		},
+		{
+			name:  "gradlew wrapper",
+			files: map[string]string{"build.gradle.kts": "", "gradlew": "#!/bin/sh\n"},
+			want: CommandSuggestions{
+				Install: "./gradlew dependencies",
+				Dev:     "./gradlew run",
+				Test:    "./gradlew test",
+				Build:   "./gradlew build",
+				Clear:   "./gradlew clean",
+			},
+		},
+		{
+			name: "Android app module",
+			files: map[string]string{
+				"settings.gradle.kts":  `include(":app")`,
+				"gradlew":              "",
+				"app/build.gradle.kts": `plugins { id("com.android.application") }`,
+			},
+			want: CommandSuggestions{
+				Install: "./gradlew dependencies",
+				Dev:     "./gradlew installDebug",
+				Test:    "./gradlew testDebugUnitTest",
+				Build:   "./gradlew assembleDebug",
+				Clear:   "./gradlew clean",
+			},
+		},
+		{
+			name:  "Kotlin Multiplatform",
+			files: map[string]string{"build.gradle.kts": `plugins { kotlin("multiplatform") }`, "gradlew": ""},
+			want: CommandSuggestions{
+				Install: "./gradlew dependencies",
+				Test:    "./gradlew allTests",
+				Build:   "./gradlew assemble",
+				Clear:   "./gradlew clean",
+			},
+		},
+		{
+			name:  "Spring Boot",
+			files: map[string]string{"build.gradle": "id 'org.springframework.boot' version '3.3.0'", "gradlew": ""},
+			want: CommandSuggestions{
+				Install: "./gradlew dependencies",
+				Dev:     "./gradlew bootRun",
+				Test:    "./gradlew test",
+				Build:   "./gradlew build",
+				Clear:   "./gradlew clean",
+			},
+		},
+		{
+			name:  "Quarkus",
+			files: map[string]string{"build.gradle.kts": `id("io.quarkus")`},
+			want: CommandSuggestions{
+				Install: "gradle dependencies",
+				Dev:     "gradle quarkusDev",
+				Test:    "gradle test",
+				Build:   "gradle build",
+				Clear:   "gradle clean",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, projectType := SuggestProjectCommands(writeProject(t, tt.files))
+			if projectType != Gradle {
+				t.Fatalf("SuggestProjectCommands() type = %s, want %s", projectType, Gradle)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("SuggestProjectCommands() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
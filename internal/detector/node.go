@@ -0,0 +1,169 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackageManager is a Node.js package manager
+type PackageManager string
+
+const (
+	Npm  PackageManager = "npm"
+	Pnpm PackageManager = "pnpm"
+	Yarn PackageManager = "yarn"
+	Bun  PackageManager = "bun"
+)
+
+// lockfiles maps each lockfile to the package manager that writes it, in
+// the order they are checked
+var lockfiles = []struct {
+	file    string
+	manager PackageManager
+}{
+	{"pnpm-lock.yaml", Pnpm},
+	{"yarn.lock", Yarn},
+	{"bun.lockb", Bun},
+	{"bun.lock", Bun},
+	{"package-lock.json", Npm},
+	{"npm-shrinkwrap.json", Npm},
+}
+
+// NodeSetup describes how a Node.js project installs its packages
+type NodeSetup struct {
+	Manager   PackageManager
+	Source    string   // What decided the manager, e.g. "pnpm-lock.yaml"; empty for the npm default
+	Lockfiles []string // Lockfiles found in the project
+	Conflict  string   // Warning when the lockfiles disagree, empty otherwise
+}
+
+// DetectNodeSetup finds the package manager of a Node.js project. The
+// packageManager field of package.json wins, then the lockfile; without
+// either npm is assumed.
+func DetectNodeSetup(projectPath string) NodeSetup {
+	setup := NodeSetup{Manager: Npm}
+
+	// Collect the lockfiles and the managers they belong to
+	var managers []PackageManager
+	for _, l := range lockfiles {
+		if !fileExists(filepath.Join(projectPath, l.file)) {
+			continue
+		}
+		setup.Lockfiles = append(setup.Lockfiles, l.file)
+		if !containsManager(managers, l.manager) {
+			managers = append(managers, l.manager)
+		}
+	}
+
+	if len(managers) > 0 {
+		setup.Manager, setup.Source = managers[0], setup.Lockfiles[0]
+	}
+
+	fromField, ok := packageManagerField(projectPath)
+	if ok {
+		setup.Manager, setup.Source = fromField, "packageManager in package.json"
+	}
+
+	// Several managers' lockfiles, or a field that none of them agree with,
+	// means installs differ depending on who runs them
+	if len(managers) > 1 || (ok && len(managers) == 1 && managers[0] != fromField) {
+		setup.Conflict = fmt.Sprintf("found %s; using %s. Remove the lockfiles of other package managers so everyone installs the same way",
+			strings.Join(setup.Lockfiles, ", "), setup)
+	}
+
+	return setup
+}
+
+// String describes the manager and why it was picked, e.g.
+// "pnpm (from pnpm-lock.yaml)"
+func (s NodeSetup) String() string {
+	if s.Source == "" {
+		return fmt.Sprintf("%s (no lockfile found)", s.Manager)
+	}
+	return fmt.Sprintf("%s (from %s)", s.Manager, s.Source)
+}
+
+// packageManagerField reads the packageManager field of package.json
+// ("pnpm@9.1.0") and returns the manager if tz knows it
+func packageManagerField(projectPath string) (PackageManager, bool) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if err != nil {
+		return "", false
+	}
+
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(pkg.PackageManager, "@")
+	switch manager := PackageManager(name); manager {
+	case Npm, Pnpm, Yarn, Bun:
+		return manager, true
+	}
+	return "", false
+}
+
+// nodeCommands returns the suggested commands for a package manager
+func nodeCommands(manager PackageManager) *CommandSuggestions {
+	switch manager {
+	case Pnpm:
+		return &CommandSuggestions{
+			Install: "pnpm install",
+			Dev:     "pnpm dev",
+			Test:    "pnpm test",
+			Build:   "pnpm build",
+			Clear:   "rm -rf dist",
+			AddDev:  "pnpm add -D",
+		}
+	case Yarn:
+		return &CommandSuggestions{
+			Install: "yarn install",
+			Dev:     "yarn dev",
+			Test:    "yarn test",
+			Build:   "yarn build",
+			Clear:   "rm -rf dist",
+			AddDev:  "yarn add -D",
+		}
+	case Bun:
+		// "bun test" runs bun's own test runner, not the test script
+		return &CommandSuggestions{
+			Install: "bun install",
+			Dev:     "bun run dev",
+			Test:    "bun run test",
+			Build:   "bun run build",
+			Clear:   "rm -rf dist",
+			AddDev:  "bun add -d",
+		}
+	default:
+		return &CommandSuggestions{
+			Install: "npm install",
+			Dev:     "npm run dev",
+			Test:    "npm test",
+			Build:   "npm run build",
+			Clear:   "rm -rf dist",
+			AddDev:  "npm install --save-dev",
+		}
+	}
+}
+
+// AddDev returns the command that adds development dependencies with the
+// package manager, e.g. "pnpm add -D"
+func (m PackageManager) AddDev() string {
+	return nodeCommands(m).AddDev
+}
+
+// containsManager reports whether managers includes manager
+func containsManager(managers []PackageManager, manager PackageManager) bool {
+	for _, m := range managers {
+		if m == manager {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeProject creates a project directory with the given files (slash-
+// separated paths) and contents
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDetectNodeSetup(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		wantManager  PackageManager
+		wantSource   string
+		wantConflict bool
+	}{
+		{
+			name:        "no lockfile",
+			files:       map[string]string{"package.json": `{}`},
+			wantManager: Npm,
+		},
+		{
+			name:        "npm lockfile",
+			files:       map[string]string{"package.json": `{}`, "package-lock.json": `{}`},
+			wantManager: Npm,
+			wantSource:  "package-lock.json",
+		},
+		{
+			name:        "pnpm lockfile",
+			files:       map[string]string{"package.json": `{}`, "pnpm-lock.yaml": ""},
+			wantManager: Pnpm,
+			wantSource:  "pnpm-lock.yaml",
+		},
+		{
+			name:        "yarn lockfile",
+			files:       map[string]string{"package.json": `{}`, "yarn.lock": ""},
+			wantManager: Yarn,
+			wantSource:  "yarn.lock",
+		},
+		{
+			name:        "bun text and binary lockfiles",
+			files:       map[string]string{"package.json": `{}`, "bun.lock": "", "bun.lockb": ""},
+			wantManager: Bun,
+			wantSource:  "bun.lockb",
+		},
+		{
+			name:        "packageManager field",
+			files:       map[string]string{"package.json": `{"packageManager": "yarn@4.1.0"}`},
+			wantManager: Yarn,
+			wantSource:  "packageManager in package.json",
+		},
+		{
+			name:        "packageManager field agrees with the lockfile",
+			files:       map[string]string{"package.json": `{"packageManager": "pnpm@9.1.0"}`, "pnpm-lock.yaml": ""},
+			wantManager: Pnpm,
+			wantSource:  "packageManager in package.json",
+		},
+		{
+			name:         "packageManager field wins over the lockfile",
+			files:        map[string]string{"package.json": `{"packageManager": "pnpm@9.1.0"}`, "package-lock.json": `{}`},
+			wantManager:  Pnpm,
+			wantSource:   "packageManager in package.json",
+			wantConflict: true,
+		},
+		{
+			name:        "unknown packageManager ignored",
+			files:       map[string]string{"package.json": `{"packageManager": "deno@2.0.0"}`, "yarn.lock": ""},
+			wantManager: Yarn,
+			wantSource:  "yarn.lock",
+		},
+		{
+			name:         "lockfiles of several managers",
+			files:        map[string]string{"package.json": `{}`, "yarn.lock": "", "package-lock.json": `{}`},
+			wantManager:  Yarn,
+			wantSource:   "yarn.lock",
+			wantConflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := DetectNodeSetup(writeProject(t, tt.files))
+			if setup.Manager != tt.wantManager || setup.Source != tt.wantSource {
+				t.Errorf("DetectNodeSetup() = %s, %q, want %s, %q", setup.Manager, setup.Source, tt.wantManager, tt.wantSource)
+			}
+			if (setup.Conflict != "") != tt.wantConflict {
+				t.Errorf("DetectNodeSetup() conflict = %q, want conflict %v", setup.Conflict, tt.wantConflict)
+			}
+		})
+	}
+}
+
+func TestNodeCommandsFollowManager(t *testing.T) {
+	tests := []struct {
+		files       map[string]string
+		wantInstall string
+		wantAddDev  string
+	}{
+		{map[string]string{"package.json": `{}`}, "npm install", "npm install --save-dev"},
+		{map[string]string{"package.json": `{}`, "pnpm-lock.yaml": ""}, "pnpm install", "pnpm add -D"},
+		{map[string]string{"package.json": `{}`, "yarn.lock": ""}, "yarn install", "yarn add -D"},
+		{map[string]string{"package.json": `{"packageManager": "bun@1.1.0"}`}, "bun install", "bun add -d"},
+	}
+
+	for _, tt := range tests {
+		dir := writeProject(t, tt.files)
+		suggestions, projectType := SuggestProjectCommands(dir)
+		if projectType != NodeJS {
+			t.Fatalf("SuggestProjectCommands() type = %s, want %s", projectType, NodeJS)
+		}
+		if suggestions.Install != tt.wantInstall {
+			t.Errorf("Install = %q, want %q", suggestions.Install, tt.wantInstall)
+		}
+		if got := DetectNodeSetup(dir).Manager.AddDev(); got != tt.wantAddDev {
+			t.Errorf("AddDev() = %q, want %q", got, tt.wantAddDev)
+		}
+	}
+}
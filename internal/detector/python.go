@@ -0,0 +1,168 @@
+package detector
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PythonTool is a tool that manages a Python project's environment
+type PythonTool string
+
+const (
+	Uv     PythonTool = "uv"
+	Poetry PythonTool = "poetry"
+	Pdm    PythonTool = "pdm"
+	Hatch  PythonTool = "hatch"
+	Pipenv PythonTool = "pipenv"
+	Pip    PythonTool = "pip"
+)
+
+// pythonTools lists how each tool is recognized, in the order they are
+// checked: its own file (usually the lockfile), or its [tool.<name>]
+// section in pyproject.toml
+var pythonTools = []struct {
+	tool    PythonTool
+	file    string
+	section string
+	pattern *regexp.Regexp // Matches the section's header, nil without one
+}{
+	{Uv, "uv.lock", "uv", toolSection("uv")},
+	{Poetry, "poetry.lock", "poetry", toolSection("poetry")},
+	{Pdm, "pdm.lock", "pdm", toolSection("pdm")},
+	{Hatch, "hatch.toml", "hatch", toolSection("hatch")},
+	{Pipenv, "Pipfile", "", nil},
+}
+
+// toolSection matches the header of the [tool.<name>] section and its
+// subsections, e.g. [tool.poetry.dependencies]
+func toolSection(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\[tool\.` + name + `[.\]]`)
+}
+
+// PythonSetup describes how a Python project installs and runs
+type PythonSetup struct {
+	Tool   PythonTool
+	Source string // What decided the tool, e.g. "uv.lock"; empty for the pip default
+}
+
+// String describes the tool and why it was picked, e.g. "uv (from uv.lock)"
+func (s PythonSetup) String() string {
+	if s.Source == "" {
+		return fmt.Sprintf("%s (no lockfile found)", s.Tool)
+	}
+	return fmt.Sprintf("%s (from %s)", s.Tool, s.Source)
+}
+
+// DetectPythonSetup finds the tool of a Python project from its lockfile
+// or pyproject.toml; without either pip is assumed
+func DetectPythonSetup(projectPath string) PythonSetup {
+	pyproject := readFiles(projectPath, "pyproject.toml")
+
+	for _, t := range pythonTools {
+		if fileExists(filepath.Join(projectPath, t.file)) {
+			return PythonSetup{Tool: t.tool, Source: t.file}
+		}
+		if t.pattern != nil && t.pattern.MatchString(pyproject) {
+			return PythonSetup{Tool: t.tool, Source: "[tool." + t.section + "] in pyproject.toml"}
+		}
+	}
+
+	if fileExists(filepath.Join(projectPath, "requirements.txt")) {
+		return PythonSetup{Tool: Pip, Source: "requirements.txt"}
+	}
+	return PythonSetup{Tool: Pip}
+}
+
+// appEntrypoints are the modules checked for a FastAPI or Flask app
+var appEntrypoints = []string{"main.py", "app.py", "app/main.py", "app/__init__.py", "api/main.py"}
+
+// appVariable matches "app = FastAPI(" and "app = Flask(__name__)"
+var appVariable = regexp.MustCompile(`(?m)^(\w+)\s*=\s*(FastAPI|Flask)\(`)
+
+// pythonSuggestions returns the suggested commands for a Python project,
+// run through the project's tool. Django projects run manage.py; FastAPI
+// and Flask apps are started with their dev servers.
+func pythonSuggestions(projectPath string) *CommandSuggestions {
+	setup := DetectPythonSetup(projectPath)
+
+	// Commands run inside the tool's environment
+	run := ""
+	switch setup.Tool {
+	case Uv, Poetry, Pdm, Hatch, Pipenv:
+		run = string(setup.Tool) + " run "
+	}
+
+	suggestions := &CommandSuggestions{
+		Test:  run + "pytest",
+		Build: run + "python -m build",
+		Clear: "rm -rf __pycache__ dist build",
+	}
+
+	switch setup.Tool {
+	case Uv:
+		suggestions.Install = "uv sync"
+		suggestions.Build = "uv build"
+	case Poetry:
+		suggestions.Install = "poetry install"
+		suggestions.Build = "poetry build"
+	case Pdm:
+		suggestions.Install = "pdm install"
+		suggestions.Build = "pdm build"
+	case Hatch:
+		suggestions.Install = "hatch env create"
+		suggestions.Test = "hatch test"
+		suggestions.Build = "hatch build"
+	case Pipenv:
+		suggestions.Install = "pipenv install --dev"
+	default:
+		if fileExists(filepath.Join(projectPath, "requirements.txt")) {
+			suggestions.Install = "pip install -r requirements.txt"
+		} else {
+			suggestions.Install = "pip install -e ."
+		}
+	}
+
+	// Django: manage.py runs the server and, unless pytest is set up, the tests
+	if fileExists(filepath.Join(projectPath, "manage.py")) {
+		suggestions.Dev = run + "python manage.py runserver"
+		if !usesPytest(projectPath) {
+			suggestions.Test = run + "python manage.py test"
+		}
+		return suggestions
+	}
+
+	for _, file := range appEntrypoints {
+		match := appVariable.FindStringSubmatch(readFiles(projectPath, file))
+		if match == nil {
+			continue
+		}
+		module := strings.ReplaceAll(strings.TrimSuffix(strings.TrimSuffix(file, ".py"), "/__init__"), "/", ".")
+		if match[2] == "FastAPI" {
+			suggestions.Dev = fmt.Sprintf("%suvicorn %s:%s --reload", run, module, match[1])
+		} else {
+			suggestions.Dev = fmt.Sprintf("%sflask --app %s:%s run --debug", run, module, match[1])
+		}
+		return suggestions
+	}
+
+	// A plain script, if there is an obvious one
+	for _, file := range []string{"main.py", "app.py"} {
+		if fileExists(filepath.Join(projectPath, file)) {
+			suggestions.Dev = run + "python " + file
+			break
+		}
+	}
+
+	return suggestions
+}
+
+// usesPytest reports whether the project configures or depends on pytest
+func usesPytest(projectPath string) bool {
+	if fileExists(filepath.Join(projectPath, "pytest.ini")) || fileExists(filepath.Join(projectPath, "conftest.py")) {
+		return true
+	}
+	deps := readFiles(projectPath, "pyproject.toml", "requirements.txt", "requirements-dev.txt", "Pipfile", "setup.cfg")
+	return strings.Contains(deps, "pytest")
+}
@@ -0,0 +1,137 @@
+package detector
+
+import "testing"
+
+func TestDetectPythonSetup(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantTool   PythonTool
+		wantSource string
+	}{
+		{"uv lockfile", map[string]string{"pyproject.toml": "", "uv.lock": ""}, Uv, "uv.lock"},
+		{"poetry lockfile", map[string]string{"pyproject.toml": "", "poetry.lock": ""}, Poetry, "poetry.lock"},
+		{"pdm lockfile", map[string]string{"pyproject.toml": "", "pdm.lock": ""}, Pdm, "pdm.lock"},
+		{"hatch.toml", map[string]string{"pyproject.toml": "", "hatch.toml": ""}, Hatch, "hatch.toml"},
+		{"Pipfile", map[string]string{"Pipfile": ""}, Pipenv, "Pipfile"},
+		{
+			name:       "poetry section",
+			files:      map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"x\"\n"},
+			wantTool:   Poetry,
+			wantSource: "[tool.poetry] in pyproject.toml",
+		},
+		{
+			name:       "poetry subsection",
+			files:      map[string]string{"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\n"},
+			wantTool:   Poetry,
+			wantSource: "[tool.poetry] in pyproject.toml",
+		},
+		{
+			name:       "uv section",
+			files:      map[string]string{"pyproject.toml": "[project]\nname = \"x\"\n\n[tool.uv]\ndev-dependencies = []\n"},
+			wantTool:   Uv,
+			wantSource: "[tool.uv] in pyproject.toml",
+		},
+		{
+			name:     "similar section names don't count",
+			files:    map[string]string{"pyproject.toml": "[tool.pdmx]\n[tool.uvicorn]\n"},
+			wantTool: Pip,
+		},
+		{
+			name:       "lockfile wins over another tool's section",
+			files:      map[string]string{"pyproject.toml": "[tool.pdm]\n", "uv.lock": ""},
+			wantTool:   Uv,
+			wantSource: "uv.lock",
+		},
+		{"requirements.txt", map[string]string{"requirements.txt": "flask\n"}, Pip, "requirements.txt"},
+		{"nothing", map[string]string{"setup.py": ""}, Pip, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := DetectPythonSetup(writeProject(t, tt.files))
+			if setup.Tool != tt.wantTool || setup.Source != tt.wantSource {
+				t.Errorf("DetectPythonSetup() = %s, %q, want %s, %q", setup.Tool, setup.Source, tt.wantTool, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestPythonSuggestions(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantInstall string
+		wantDev     string
+		wantTest    string
+		wantBuild   string
+	}{
+		{
+			name:        "uv",
+			files:       map[string]string{"pyproject.toml": "", "uv.lock": "", "main.py": "print('hi')\n"},
+			wantInstall: "uv sync",
+			wantDev:     "uv run python main.py",
+			wantTest:    "uv run pytest",
+			wantBuild:   "uv build",
+		},
+		{
+			name:        "poetry with FastAPI",
+			files:       map[string]string{"pyproject.toml": "[tool.poetry]\n", "app/main.py": "api = FastAPI()\n"},
+			wantInstall: "poetry install",
+			wantDev:     "poetry run uvicorn app.main:api --reload",
+			wantTest:    "poetry run pytest",
+			wantBuild:   "poetry build",
+		},
+		{
+			name:        "pdm with Flask",
+			files:       map[string]string{"pyproject.toml": "", "pdm.lock": "", "app.py": "app = Flask(__name__)\n"},
+			wantInstall: "pdm install",
+			wantDev:     "pdm run flask --app app:app run --debug",
+			wantTest:    "pdm run pytest",
+			wantBuild:   "pdm build",
+		},
+		{
+			name:        "hatch",
+			files:       map[string]string{"pyproject.toml": "[tool.hatch.envs.default]\n"},
+			wantInstall: "hatch env create",
+			wantTest:    "hatch test",
+			wantBuild:   "hatch build",
+		},
+		{
+			name:        "pipenv with Django",
+			files:       map[string]string{"Pipfile": "[packages]\ndjango = \"*\"\n", "manage.py": ""},
+			wantInstall: "pipenv install --dev",
+			wantDev:     "pipenv run python manage.py runserver",
+			wantTest:    "pipenv run python manage.py test",
+			wantBuild:   "pipenv run python -m build",
+		},
+		{
+			name:        "pip with Django and pytest",
+			files:       map[string]string{"requirements.txt": "django\npytest-django\n", "manage.py": ""},
+			wantInstall: "pip install -r requirements.txt",
+			wantDev:     "python manage.py runserver",
+			wantTest:    "pytest",
+			wantBuild:   "python -m build",
+		},
+		{
+			name:        "pip without requirements",
+			files:       map[string]string{"pyproject.toml": "[project]\nname = \"x\"\n"},
+			wantInstall: "pip install -e .",
+			wantTest:    "pytest",
+			wantBuild:   "python -m build",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, projectType := SuggestProjectCommands(writeProject(t, tt.files))
+			if projectType != Python {
+				t.Fatalf("SuggestProjectCommands() type = %s, want %s", projectType, Python)
+			}
+			if got.Install != tt.wantInstall || got.Dev != tt.wantDev || got.Test != tt.wantTest || got.Build != tt.wantBuild {
+				t.Errorf("SuggestProjectCommands() = %+v, want install %q, dev %q, test %q, build %q",
+					*got, tt.wantInstall, tt.wantDev, tt.wantTest, tt.wantBuild)
+			}
+		})
+	}
+}
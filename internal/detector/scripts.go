@@ -0,0 +1,156 @@
+package detector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// scriptCandidates ranks the script names that likely implement each
+// built-in command; the first one the project has is suggested
+var scriptCandidates = map[string][]string{
+	"dev":   {"dev", "start", "serve", "develop", "watch"},
+	"test":  {"test", "test:unit", "tests", "spec"},
+	"build": {"build", "compile", "bundle", "dist"},
+	"clear": {"clean", "clear"},
+}
+
+// lifecycleScripts run on their own during install or publish, so they
+// are never offered as commands
+var lifecycleScripts = map[string]bool{
+	"preinstall": true, "install": true, "postinstall": true,
+	"prepare": true, "prepublish": true, "prepublishOnly": true,
+	"prepack": true, "postpack": true, "publish": true, "postpublish": true,
+}
+
+// CustomCommand is a script or target of the project offered as a custom
+// tz command
+type CustomCommand struct {
+	Name        string // Command name in tz, e.g. "test-e2e" for "test:e2e"
+	Command     string // Shell command that runs it, e.g. "pnpm run lint"
+	Description string
+	Source      string // File it was found in, e.g. "package.json"
+}
+
+// readScripts returns the scripts of package.json. ok is false when there
+// is no readable package.json.
+func readScripts(projectPath string) (map[string]string, bool) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if err != nil {
+		return nil, false
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, false
+	}
+	return pkg.Scripts, true
+}
+
+// nodeSuggestions returns the suggested commands for a Node.js project:
+// the built-ins run the project's own scripts, picked from the ranked
+// candidates. A built-in without a matching script gets no suggestion, so
+// it isn't mapped to a script that doesn't exist.
+func nodeSuggestions(projectPath string) *CommandSuggestions {
+	manager := DetectNodeSetup(projectPath).Manager
+	suggestions := nodeCommands(manager)
+
+	scripts, ok := readScripts(projectPath)
+	if !ok {
+		return suggestions
+	}
+
+	pick := func(commandName string) string {
+		if script := matchScript(scripts, commandName); script != "" {
+			return runScript(manager, script)
+		}
+		return ""
+	}
+	suggestions.Dev = pick("dev")
+	suggestions.Test = pick("test")
+	suggestions.Build = pick("build")
+	if clear := pick("clear"); clear != "" {
+		suggestions.Clear = clear
+	}
+
+	return suggestions
+}
+
+// matchScript returns the best script for a built-in command, or "" if
+// the project has none of the candidates
+func matchScript(scripts map[string]string, commandName string) string {
+	for _, name := range scriptCandidates[commandName] {
+		if _, ok := scripts[name]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// OtherScripts returns the package.json scripts that no built-in command
+// suggestion uses, sorted by name. Lifecycle scripts and pre/post hooks of
+// other scripts are left out.
+func OtherScripts(projectPath string) []CustomCommand {
+	if DetectProjectType(projectPath) != NodeJS {
+		return nil
+	}
+	scripts, ok := readScripts(projectPath)
+	if !ok {
+		return nil
+	}
+
+	used := map[string]bool{}
+	for commandName := range scriptCandidates {
+		used[matchScript(scripts, commandName)] = true
+	}
+
+	manager := DetectNodeSetup(projectPath).Manager
+	var others []CustomCommand
+	for name := range scripts {
+		if used[name] || lifecycleScripts[name] || isHook(scripts, name) {
+			continue
+		}
+		others = append(others, CustomCommand{
+			Name:    strings.ReplaceAll(name, ":", "-"),
+			Command: runScript(manager, name),
+			Source:  "package.json",
+		})
+	}
+
+	sort.Slice(others, func(i, j int) bool { return others[i].Name < others[j].Name })
+	return others
+}
+
+// isHook reports whether the script is a pre or post hook of another
+// script, which npm runs automatically around it
+func isHook(scripts map[string]string, name string) bool {
+	for _, prefix := range []string{"pre", "post"} {
+		if target, ok := strings.CutPrefix(name, prefix); ok {
+			if _, exists := scripts[target]; exists {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runScript returns the command that runs a package.json script with the
+// package manager. The short form ("pnpm dev") is only used for names that
+// can't clash with the manager's own subcommands.
+func runScript(manager PackageManager, script string) string {
+	switch script {
+	case "test", "start":
+		if manager != Bun {
+			return string(manager) + " " + script
+		}
+	case "dev", "build":
+		if manager == Pnpm || manager == Yarn {
+			return string(manager) + " " + script
+		}
+	}
+	return string(manager) + " run " + script
+}
@@ -0,0 +1,124 @@
+package detector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodeSuggestionsFromScripts(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    CommandSuggestions
+		wantAll bool // Compare all fields, not only the script-based ones
+	}{
+		{
+			name:  "standard scripts with npm",
+			files: map[string]string{"package.json": `{"scripts": {"dev": "vite", "test": "vitest", "build": "vite build", "clean": "rimraf dist"}}`},
+			want:  CommandSuggestions{Dev: "npm run dev", Test: "npm test", Build: "npm run build", Clear: "npm run clean"},
+		},
+		{
+			name: "short forms with pnpm",
+			files: map[string]string{
+				"package.json":   `{"scripts": {"dev": "vite", "test": "vitest", "build": "vite build"}}`,
+				"pnpm-lock.yaml": "",
+			},
+			want: CommandSuggestions{Dev: "pnpm dev", Test: "pnpm test", Build: "pnpm build", Clear: "rm -rf dist"},
+		},
+		{
+			name:  "bun runs the test script, not its own runner",
+			files: map[string]string{"package.json": `{"packageManager": "bun@1.1.0", "scripts": {"start": "bun src/index.ts", "test": "vitest"}}`},
+			want:  CommandSuggestions{Dev: "bun run start", Test: "bun run test", Clear: "rm -rf dist"},
+		},
+		{
+			name:  "ranked candidates",
+			files: map[string]string{"package.json": `{"scripts": {"serve": "x", "start": "y", "test:unit": "z", "compile": "tsc", "bundle": "esbuild"}}`},
+			want:  CommandSuggestions{Dev: "npm start", Test: "npm run test:unit", Build: "npm run compile", Clear: "rm -rf dist"},
+		},
+		{
+			name:  "missing scripts get no suggestion",
+			files: map[string]string{"package.json": `{"scripts": {"lint": "eslint ."}}`},
+			want:  CommandSuggestions{Clear: "rm -rf dist"},
+		},
+		{
+			name:  "no scripts",
+			files: map[string]string{"package.json": `{}`},
+			want:  CommandSuggestions{Clear: "rm -rf dist"},
+		},
+		{
+			name:    "unreadable package.json keeps the defaults",
+			files:   map[string]string{"package.json": `{"scripts": `},
+			want:    *nodeCommands(Npm),
+			wantAll: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := *nodeSuggestions(writeProject(t, tt.files))
+			if !tt.wantAll {
+				// Install and AddDev come from the package manager only
+				got.Install, got.AddDev = "", ""
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nodeSuggestions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOtherScripts(t *testing.T) {
+	dir := writeProject(t, map[string]string{
+		"package.json": `{"scripts": {
+			"dev": "vite",
+			"test": "vitest",
+			"test:e2e": "playwright test",
+			"pretest": "tsc",
+			"postinstall": "patch-package",
+			"prepare": "husky",
+			"lint": "eslint .",
+			"prelint": "echo linting",
+			"preview": "vite preview"
+		}}`,
+		"yarn.lock": "",
+	})
+
+	want := []CustomCommand{
+		{Name: "lint", Command: "yarn run lint", Source: "package.json"},
+		{Name: "preview", Command: "yarn run preview", Source: "package.json"},
+		{Name: "test-e2e", Command: "yarn run test:e2e", Source: "package.json"},
+	}
+	if got := OtherScripts(dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("OtherScripts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOtherScriptsOutsideNode(t *testing.T) {
+	dir := writeProject(t, map[string]string{"go.mod": "module x\n"})
+	if got := OtherScripts(dir); got != nil {
+		t.Errorf("OtherScripts() = %+v, want none", got)
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	tests := []struct {
+		manager PackageManager
+		script  string
+		want    string
+	}{
+		{Npm, "test", "npm test"},
+		{Npm, "start", "npm start"},
+		{Npm, "dev", "npm run dev"},
+		{Pnpm, "dev", "pnpm dev"},
+		{Yarn, "build", "yarn build"},
+		{Yarn, "lint", "yarn run lint"},
+		{Bun, "test", "bun run test"},
+		{Bun, "dev", "bun run dev"},
+	}
+
+	for _, tt := range tests {
+		if got := runScript(tt.manager, tt.script); got != tt.want {
+			t.Errorf("runScript(%s, %q) = %q, want %q", tt.manager, tt.script, got, tt.want)
+		}
+	}
+}
@@ -7,19 +7,16 @@ type CommandSuggestions struct {
 	Test    string
 	Build   string
 	Clear   string
+	AddDev  string // Adds a development dependency, e.g. "pnpm add -D"
 }
 
-// SuggestCommands returns suggested commands based on project type
+// SuggestCommands returns suggested commands based on project type. These
+// are the defaults; SuggestProjectCommands refines them with what the
+// project contains (package manager, scripts, build wrappers, tools).
 func SuggestCommands(projectType ProjectType) *CommandSuggestions {
 	switch projectType {
 	case NodeJS:
-		return &CommandSuggestions{
-			Install: "npm install",
-			Dev:     "npm run dev",
-			Test:    "npm test",
-			Build:   "npm run build",
-			Clear:   "rm -rf dist",
-		}
+		return nodeCommands(Npm)
 	case Go:
 		return &CommandSuggestions{
 			Install: "go mod download",
@@ -52,27 +49,65 @@ func SuggestCommands(projectType ProjectType) *CommandSuggestions {
 			Build:   "bundle exec rake build",
 			Clear:   "rm -rf tmp",
 		}
-	case Java:
+	case Maven:
 		return &CommandSuggestions{
-			Install: "mvn install",
-			Dev:     "mvn spring-boot:run",
+			Install: "mvn install -DskipTests",
+			Dev:     "mvn exec:java",
 			Test:    "mvn test",
 			Build:   "mvn package",
 			Clear:   "mvn clean",
 		}
+	case Gradle:
+		return &CommandSuggestions{
+			Install: "gradle dependencies",
+			Dev:     "gradle run",
+			Test:    "gradle test",
+			Build:   "gradle build",
+			Clear:   "gradle clean",
+		}
 	default:
 		return nil
 	}
 }
 
-// GetSuggestion returns a suggested command for a specific command type
-func GetSuggestion(projectPath, commandName string) (string, ProjectType) {
+// SuggestProjectCommands detects the project's type and returns the
+// commands suggested for it. Targets of a Makefile, justfile or Taskfile
+// take precedence over the project type's defaults.
+func SuggestProjectCommands(projectPath string) (*CommandSuggestions, ProjectType) {
 	projectType := DetectProjectType(projectPath)
 	if projectType == Unknown {
-		return "", Unknown
+		return nil, Unknown
 	}
 
-	suggestions := SuggestCommands(projectType)
+	var suggestions *CommandSuggestions
+	switch projectType {
+	case NodeJS:
+		suggestions = nodeSuggestions(projectPath)
+	case Python:
+		suggestions = pythonSuggestions(projectPath)
+	case Maven:
+		suggestions = mavenSuggestions(projectPath)
+	case Gradle:
+		suggestions = gradleSuggestions(projectPath)
+	default:
+		suggestions = SuggestCommands(projectType)
+	}
+	return applyTaskRunners(projectPath, suggestions), projectType
+}
+
+// OtherCommands returns the project's package.json scripts and task runner
+// targets that no built-in suggestion uses
+func OtherCommands(projectPath string) []CustomCommand {
+	others := OtherScripts(projectPath)
+	for _, r := range TaskRunners {
+		others = append(others, r.Others(projectPath)...)
+	}
+	return others
+}
+
+// GetSuggestion returns a suggested command for a specific command type
+func GetSuggestion(projectPath, commandName string) (string, ProjectType) {
+	suggestions, projectType := SuggestProjectCommands(projectPath)
 	if suggestions == nil {
 		return "", Unknown
 	}
@@ -0,0 +1,382 @@
+package detector
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TaskRunner is a tool that runs named targets from a file in the project
+type TaskRunner struct {
+	Name  string   // Command that runs a target, e.g. "make"
+	Files []string // File names the runner reads, in the order it looks for them
+	parse func(r io.Reader) []Target
+}
+
+// Target is a target defined in a Makefile, justfile or Taskfile
+type Target struct {
+	Name        string
+	Description string // Comment or desc documenting the target, if any
+}
+
+// TaskRunners lists the supported task runners. When a project has
+// several, the earlier ones are preferred.
+var TaskRunners = []TaskRunner{
+	{Name: "just", Files: []string{"justfile", "Justfile", ".justfile"}, parse: parseJustfile},
+	{Name: "task", Files: []string{"Taskfile.yml", "Taskfile.yaml", "taskfile.yml", "taskfile.yaml"}, parse: parseTaskfile},
+	{Name: "make", Files: []string{"GNUmakefile", "makefile", "Makefile"}, parse: parseMakefile},
+}
+
+// targetCandidates ranks the target names that likely implement each
+// built-in command
+var targetCandidates = map[string][]string{
+	"install": {"install", "deps", "setup", "bootstrap"},
+	"dev":     {"dev", "run", "serve", "start", "watch"},
+	"test":    {"test", "tests"},
+	"build":   {"build", "compile", "all"},
+	"clear":   {"clean", "clear"},
+}
+
+// FindTaskRunner returns the task runner with the given name ("make",
+// "just" or "task")
+func FindTaskRunner(name string) (TaskRunner, bool) {
+	for _, r := range TaskRunners {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return TaskRunner{}, false
+}
+
+// File returns the runner's file in the project, or "" if there is none
+func (r TaskRunner) File(projectPath string) string {
+	for _, name := range r.Files {
+		if fileExists(filepath.Join(projectPath, name)) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Targets returns the targets defined in the project, or nil if the
+// project has no file for the runner
+func (r TaskRunner) Targets(projectPath string) []Target {
+	file := r.File(projectPath)
+	if file == "" {
+		return nil
+	}
+	f, err := os.Open(filepath.Join(projectPath, file))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	return r.parse(f)
+}
+
+// Builtins returns the built-in commands (by name) that the project's
+// targets implement, with the command that runs the matching target
+func (r TaskRunner) Builtins(projectPath string) map[string]CustomCommand {
+	targets := map[string]Target{}
+	for _, t := range r.Targets(projectPath) {
+		targets[t.Name] = t
+	}
+
+	builtins := map[string]CustomCommand{}
+	for commandName, candidates := range targetCandidates {
+		for _, name := range candidates {
+			if t, ok := targets[name]; ok {
+				builtins[commandName] = CustomCommand{
+					Name:        commandName,
+					Command:     r.Name + " " + name,
+					Description: t.Description,
+					Source:      r.File(projectPath),
+				}
+				break
+			}
+		}
+	}
+	return builtins
+}
+
+// BuiltinTarget returns the target suggested for a built-in command, from
+// the first task runner that has a matching one
+func BuiltinTarget(projectPath, commandName string) (CustomCommand, bool) {
+	for _, r := range TaskRunners {
+		if c, ok := r.Builtins(projectPath)[commandName]; ok {
+			return c, true
+		}
+	}
+	return CustomCommand{}, false
+}
+
+// Others returns the targets that none of the built-in commands use, as
+// custom commands sorted by name. Targets named like a built-in command
+// are left out too: a Makefile with "clean" and "clear" maps clear to
+// "make clean", and its "clear" target can't be a custom command.
+func (r TaskRunner) Others(projectPath string) []CustomCommand {
+	targets := r.Targets(projectPath)
+	used := map[string]bool{}
+	for _, c := range r.Builtins(projectPath) {
+		used[strings.TrimPrefix(c.Command, r.Name+" ")] = true
+	}
+
+	var others []CustomCommand
+	for _, t := range targets {
+		name := strings.ReplaceAll(t.Name, ":", "-")
+		if _, builtin := targetCandidates[name]; builtin || used[t.Name] {
+			continue
+		}
+		others = append(others, CustomCommand{
+			Name:        name,
+			Command:     r.Name + " " + t.Name,
+			Description: t.Description,
+			Source:      r.File(projectPath),
+		})
+	}
+
+	sort.Slice(others, func(i, j int) bool { return others[i].Name < others[j].Name })
+	return others
+}
+
+// applyTaskRunners replaces the suggestions for built-ins that a target in
+// the project implements. For each built-in the first runner with a
+// matching target wins.
+func applyTaskRunners(projectPath string, suggestions *CommandSuggestions) *CommandSuggestions {
+	if suggestions == nil {
+		suggestions = &CommandSuggestions{}
+	}
+
+	for commandName := range targetCandidates {
+		c, ok := BuiltinTarget(projectPath, commandName)
+		if !ok {
+			continue
+		}
+		switch commandName {
+		case "install":
+			suggestions.Install = c.Command
+		case "dev":
+			suggestions.Dev = c.Command
+		case "test":
+			suggestions.Test = c.Command
+		case "build":
+			suggestions.Build = c.Command
+		case "clear":
+			suggestions.Clear = c.Command
+		}
+	}
+
+	return suggestions
+}
+
+var (
+	// makeRule matches "name other: deps ## description" and double-colon
+	// rules, but not variable assignments ("X := y", "X ::= y")
+	makeRule = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_./ -]*?)\s*::?([^:=]|$)`)
+
+	// justRecipe matches "name arg='x': deps" and "@name:", but not
+	// assignments ("x := y")
+	justRecipe = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)(\s[^:]*)?:([^=]|$)`)
+)
+
+// parseMakefile returns the explicit targets of a Makefile. Special
+// targets (.PHONY), pattern rules (%.o) and file targets with an extension
+// are skipped. A "## text" after the rule or a "# text" line above it is
+// used as the description. The body of a "define ... endef" variable is
+// text, not rules, and conditional and include lines are directives.
+func parseMakefile(r io.Reader) []Target {
+	var targets []Target
+	seen := map[string]bool{}
+	defines := 0 // Nesting depth of define blocks
+
+	forEachLine(r, func(line, comment string) {
+		// Recipe lines start with a tab
+		if strings.HasPrefix(line, "\t") {
+			return
+		}
+		switch makeDirective(line) {
+		case "define":
+			defines++
+			return
+		case "endef":
+			defines = max(defines-1, 0)
+			return
+		case "ifeq", "ifneq", "ifdef", "ifndef", "else", "endif", "include", "-include", "sinclude":
+			return
+		}
+		if defines > 0 {
+			return
+		}
+		match := makeRule.FindStringSubmatch(line)
+		if match == nil {
+			return
+		}
+
+		description := comment
+		if _, doc, ok := strings.Cut(line, "##"); ok {
+			description = strings.TrimSpace(doc)
+		}
+
+		for _, name := range strings.Fields(match[1]) {
+			if seen[name] || strings.HasPrefix(name, ".") || strings.ContainsAny(name, "%$/") || filepath.Ext(name) != "" {
+				continue
+			}
+			seen[name] = true
+			targets = append(targets, Target{Name: name, Description: description})
+		}
+	})
+
+	return targets
+}
+
+// makeDirective returns the first word of a Makefile line, after the
+// modifiers that can precede "define"
+func makeDirective(line string) string {
+	fields := strings.Fields(line)
+	for len(fields) > 1 && (fields[0] == "override" || fields[0] == "export" || fields[0] == "private") {
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// parseJustfile returns the public recipes of a justfile. Recipes starting
+// with "_" or marked [private] are skipped. A "# text" line above a recipe
+// is used as its description.
+func parseJustfile(r io.Reader) []Target {
+	var targets []Target
+	private := false
+
+	forEachLine(r, func(line, comment string) {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			return
+		}
+		if strings.HasPrefix(line, "[") {
+			if strings.Contains(line, "private") {
+				private = true
+			}
+			return
+		}
+
+		match := justRecipe.FindStringSubmatch(line)
+		wasPrivate := private
+		private = false
+		if match == nil {
+			return
+		}
+
+		name := match[1]
+		switch name {
+		case "set", "alias", "export", "import", "mod":
+			return
+		}
+		if wasPrivate || strings.HasPrefix(name, "_") {
+			return
+		}
+		targets = append(targets, Target{Name: name, Description: comment})
+	})
+
+	return targets
+}
+
+// parseTaskfile returns the tasks of a Taskfile. It reads just enough YAML
+// to find the keys under "tasks:" and their desc; tasks marked internal are
+// skipped.
+func parseTaskfile(r io.Reader) []Target {
+	var targets []Target
+	inTasks := false
+	taskIndent, propIndent := -1, -1
+	internal := false
+
+	// finish drops the last task if it turned out to be internal
+	finish := func() {
+		if internal && len(targets) > 0 {
+			targets = targets[:len(targets)-1]
+		}
+		internal = false
+	}
+
+	forEachLine(r, func(line, _ string) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			return
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if indent == 0 {
+			finish()
+			inTasks = trimmed == "tasks:"
+			taskIndent = -1
+			return
+		}
+		if !inTasks {
+			return
+		}
+
+		if taskIndent == -1 {
+			taskIndent = indent
+		}
+		key, value, ok := yamlKey(trimmed)
+		if !ok {
+			return
+		}
+
+		// Only the task's own keys count, not those nested in cmds or vars
+		if indent == taskIndent {
+			finish()
+			targets = append(targets, Target{Name: key})
+			propIndent = -1
+			return
+		}
+		if propIndent == -1 {
+			propIndent = indent
+		}
+		if indent != propIndent || len(targets) == 0 {
+			return
+		}
+		switch key {
+		case "desc":
+			targets[len(targets)-1].Description = value
+		case "internal":
+			internal = value == "true"
+		}
+	})
+	finish()
+
+	return targets
+}
+
+// yamlKey splits a "key: value" line. Keys may contain colons themselves
+// ("gen:proto:"), so only a colon followed by a space or the end of the
+// line ends the key.
+func yamlKey(line string) (string, string, bool) {
+	key, value, ok := strings.Cut(line, ": ")
+	if !ok {
+		if !strings.HasSuffix(line, ":") {
+			return "", "", false
+		}
+		key = strings.TrimSuffix(line, ":")
+	}
+	return strings.Trim(key, `"'`), strings.Trim(strings.TrimSpace(value), `"'`), true
+}
+
+// forEachLine calls fn with each line read from r and the text of the
+// "# comment" lines directly above it
+func forEachLine(r io.Reader, fn func(line, comment string)) {
+	var comment []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if text, ok := strings.CutPrefix(line, "#"); ok {
+			comment = append(comment, strings.TrimSpace(strings.TrimLeft(text, "#")))
+			continue
+		}
+		fn(line, strings.Join(comment, " "))
+		comment = nil
+	}
+}
@@ -0,0 +1,249 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMakefile(t *testing.T) {
+	tests := []struct {
+		name     string
+		makefile string
+		want     []Target
+	}{
+		{
+			name:     "rules",
+			makefile: "build:\n\tgo build\n\ntest: build\n\tgo test ./...\n",
+			want:     []Target{{Name: "build"}, {Name: "test"}},
+		},
+		{
+			name:     "descriptions",
+			makefile: "# Compile the binary\nbuild:\n\tgo build\n\nlint: ## Run the linters\n\tgolangci-lint run\n",
+			want:     []Target{{Name: "build", Description: "Compile the binary"}, {Name: "lint", Description: "Run the linters"}},
+		},
+		{
+			name:     "several targets in one rule",
+			makefile: "fmt vet: deps\n\tgo $@ ./...\n",
+			want:     []Target{{Name: "fmt"}, {Name: "vet"}},
+		},
+		{
+			name:     "special, pattern and file targets skipped",
+			makefile: ".PHONY: build\n%.o: %.c\n\tcc -c $<\nmain.o: main.c\nbin/tz: main.go\nbuild:\n",
+			want:     []Target{{Name: "build"}},
+		},
+		{
+			name:     "assignments skipped",
+			makefile: "GO := go\nFLAGS ::= -v\nOUT = bin\nbuild:\n",
+			want:     []Target{{Name: "build"}},
+		},
+		{
+			name:     "double-colon rules",
+			makefile: "clean:: \n\trm -rf bin\nclean::\n\trm -rf dist\n",
+			want:     []Target{{Name: "clean"}},
+		},
+		{
+			name:     "duplicates kept once",
+			makefile: "build: a\nbuild: b\n",
+			want:     []Target{{Name: "build"}},
+		},
+		{
+			name:     "define blocks skipped",
+			makefile: "define FOO\nbar: baz\nendef\n\noverride define BAR =\nqux:\nendef\nbuild:\n",
+			want:     []Target{{Name: "build"}},
+		},
+		{
+			name:     "conditional rules kept",
+			makefile: "ifeq ($(OS),Windows_NT)\nwin:\nelse\nunix:\nendif\n-include local.mk\n",
+			want:     []Target{{Name: "win"}, {Name: "unix"}},
+		},
+		{
+			name:     "recipe lines skipped",
+			makefile: "build:\n\techo done: yes\n",
+			want:     []Target{{Name: "build"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMakefile(strings.NewReader(tt.makefile))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMakefile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJustfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		justfile string
+		want     []Target
+	}{
+		{
+			name:     "recipes",
+			justfile: "build:\n    cargo build\n\ntest: build\n    cargo test\n",
+			want:     []Target{{Name: "build"}, {Name: "test"}},
+		},
+		{
+			name:     "parameters and quiet recipes",
+			justfile: "deploy env='staging': build\n    ./deploy {{env}}\n@fmt:\n    cargo fmt\n",
+			want:     []Target{{Name: "deploy"}, {Name: "fmt"}},
+		},
+		{
+			name:     "descriptions",
+			justfile: "# Run the server\ndev:\n    cargo run\n",
+			want:     []Target{{Name: "dev", Description: "Run the server"}},
+		},
+		{
+			name:     "private recipes skipped",
+			justfile: "_helper:\n    true\n[private]\nsecret:\n    true\npublic:\n    true\n",
+			want:     []Target{{Name: "public"}},
+		},
+		{
+			name:     "attributes of public recipes",
+			justfile: "[linux]\nbuild:\n    make\n",
+			want:     []Target{{Name: "build"}},
+		},
+		{
+			name:     "settings and assignments skipped",
+			justfile: "set shell := [\"bash\", \"-c\"]\nversion := \"1.0\"\nalias b := build\nexport RUST_LOG := \"info\"\nbuild:\n",
+			want:     []Target{{Name: "build"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseJustfile(strings.NewReader(tt.justfile))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJustfile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTaskfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		taskfile string
+		want     []Target
+	}{
+		{
+			name: "tasks",
+			taskfile: `version: '3'
+
+tasks:
+  build:
+    desc: Build the app
+    cmds:
+      - go build
+  test:
+    cmds:
+      - go test ./...
+`,
+			want: []Target{{Name: "build", Description: "Build the app"}, {Name: "test"}},
+		},
+		{
+			name: "keys with colons",
+			taskfile: `tasks:
+  gen:proto:
+    desc: "Generate protobuf code"
+    cmds:
+      - buf generate
+`,
+			want: []Target{{Name: "gen:proto", Description: "Generate protobuf code"}},
+		},
+		{
+			name: "internal tasks skipped",
+			taskfile: `tasks:
+  helper:
+    internal: true
+    cmds:
+      - echo hi
+  lint:
+    cmds:
+      - golangci-lint run
+  setup:
+    internal: true
+`,
+			want: []Target{{Name: "lint"}},
+		},
+		{
+			name: "nested keys ignored",
+			taskfile: `tasks:
+  deploy:
+    vars:
+      desc: not the description
+    cmds:
+      - task: build
+`,
+			want: []Target{{Name: "deploy"}},
+		},
+		{
+			name: "other sections ignored",
+			taskfile: `vars:
+  build: x
+tasks:
+  build:
+    cmds: [go build]
+includes:
+  docs: ./docs
+`,
+			want: []Target{{Name: "build"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTaskfile(strings.NewReader(tt.taskfile))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTaskfile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskRunnerBuiltinsAndOthers(t *testing.T) {
+	dir := t.TempDir()
+	makefile := "clean:\n\trm -rf bin\nclear:\n\trm -rf cache\nlint:\n\tgolangci-lint run\n"
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte(makefile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runner, _ := FindTaskRunner("make")
+
+	if got := runner.Builtins(dir)["clear"].Command; got != "make clean" {
+		t.Errorf("Builtins()[clear] = %q, want %q", got, "make clean")
+	}
+
+	var names []string
+	for _, c := range runner.Others(dir) {
+		names = append(names, c.Name)
+	}
+	if want := []string{"lint"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Others() = %v, want %v", names, want)
+	}
+}
+
+func TestYamlKey(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantKey   string
+		wantValue string
+		wantOK    bool
+	}{
+		{"build:", "build", "", true},
+		{"desc: Build the app", "desc", "Build the app", true},
+		{"gen:proto:", "gen:proto", "", true},
+		{`"quoted": 'value'`, "quoted", "value", true},
+		{"- go build", "", "", false},
+	}
+
+	for _, tt := range tests {
+		key, value, ok := yamlKey(tt.line)
+		if key != tt.wantKey || value != tt.wantValue || ok != tt.wantOK {
+			t.Errorf("yamlKey(%q) = %q, %q, %v, want %q, %q, %v", tt.line, key, value, ok, tt.wantKey, tt.wantValue, tt.wantOK)
+		}
+	}
+}
@@ -1,34 +1,131 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"syscall"
+	"time"
 )
 
+// ExitError reports that a command ran but did not succeed
+type ExitError struct {
+	Code   int       // Exit code; 128+n when killed by signal n, like the shell
+	Signal os.Signal // Signal that killed the command, if any
+}
+
+func (e *ExitError) Error() string {
+	if e.Signal != nil {
+		return fmt.Sprintf("command terminated by signal: %s", e.Signal)
+	}
+	return fmt.Sprintf("command failed: exit status %d", e.Code)
+}
+
+// exitError converts the error of a finished command into an *ExitError,
+// leaving other errors (e.g. the shell could not be started) as they are
+func exitError(err error) error {
+	if _, ok := err.(*ExitError); ok {
+		return err
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("command failed: %w", err)
+	}
+
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return &ExitError{Code: 128 + int(status.Signal()), Signal: status.Signal()}
+	}
+	return &ExitError{Code: exitErr.ExitCode()}
+}
+
+// Options customize how Execute launches a command
+//
+// A command with its own Stdout or Stderr runs alongside others: unless
+// Stdin is set, it gets no stdin. Only a command reading os.Stdin may take
+// over the terminal.
+type Options struct {
+	Dir    string          // Working directory, empty for the current directory
+	Env    []string        // Extra KEY=VALUE pairs added to the environment
+	Shell  string          // Shell that runs the command, defaults to sh
+	Grace  time.Duration   // How long an interrupted command may take to exit before it is killed
+	Stdout io.Writer       // Where output goes, defaults to os.Stdout
+	Stderr io.Writer       // Where errors go, defaults to os.Stderr
+	Stdin  io.Reader       // Where input comes from, see above
+	Stop   <-chan struct{} // Closing it terminates the command as if tz got SIGTERM
+}
+
+// DefaultGrace is the grace period used when Options.Grace is not set
+const DefaultGrace = 5 * time.Second
+
 // Execute runs a shell command and returns the output or error
 func Execute(command string) error {
+	return ExecuteWith(command, Options{})
+}
+
+// ExecuteIn runs a shell command with dir as its working directory.
+// An empty dir runs the command in the current directory.
+func ExecuteIn(dir, command string) error {
+	return ExecuteWith(command, Options{Dir: dir})
+}
+
+// ExecuteWith runs a shell command with the given options
+func ExecuteWith(command string, opts Options) error {
 	if command == "" {
 		return fmt.Errorf("empty command")
 	}
 
-	// Use shell to execute the command (supports pipes, redirects, etc.)
-	cmd := exec.Command("sh", "-c", command)
-	
+	cmd := shellCommand(command, opts)
+
 	// Connect to stdout and stderr
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
+	if opts.Stdout != nil || opts.Stderr != nil {
+		cmd.Stdin = nil
+		if opts.Stdout != nil {
+			cmd.Stdout = opts.Stdout
+		}
+		if opts.Stderr != nil {
+			cmd.Stderr = opts.Stderr
+		}
+	}
+	if opts.Stdin != nil {
+		cmd.Stdin = opts.Stdin
+	}
+	attached := cmd.Stdin == os.Stdin
 
-	// Run the command
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("command failed: %w", err)
+	// Run the command in its own process group so signals reach everything it starts
+	grace := opts.Grace
+	if grace <= 0 {
+		grace = DefaultGrace
+	}
+	if err := runGroup(cmd, grace, attached, opts.Stop); err != nil {
+		return exitError(err)
 	}
 
 	return nil
 }
 
+// shellCommand prepares a shell running command with the given options
+func shellCommand(command string, opts Options) *exec.Cmd {
+	// Use shell to execute the command (supports pipes, redirects, etc.)
+	// The shell may carry its own flags, e.g. "bash -eo pipefail"
+	shell := strings.Fields(opts.Shell)
+	if len(shell) == 0 {
+		shell = []string{"sh"}
+	}
+	cmd := exec.Command(shell[0], append(shell[1:], "-c", command)...)
+	cmd.Dir = opts.Dir
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	return cmd
+}
+
 // ExecuteWithOutput runs a command and returns its output as a string
 func ExecuteWithOutput(command string) (string, error) {
 	if command == "" {
@@ -0,0 +1,54 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Without process groups, signals can only be passed on to the shell itself.
+
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+var terminateSignal os.Signal = os.Kill
+
+func setProcessGroup(cmd *exec.Cmd, attached bool) bool {
+	return false
+}
+
+// signalGroup has no group to signal, so it can't reach the command's
+// children
+func signalGroup(pgid int, sig os.Signal) {
+	if process, err := os.FindProcess(pgid); err == nil {
+		process.Signal(sig)
+	}
+}
+
+func setDetached(cmd *exec.Cmd) {}
+
+// GroupAlive only knows about the process itself on this platform
+func GroupAlive(pgid int) bool {
+	process, err := os.FindProcess(pgid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// ProcessStart can't tell processes with the same PID apart on this
+// platform
+func ProcessStart(pid int) string {
+	return ""
+}
+
+func killedBySignal(err error) bool {
+	return false
+}
+
+func waitGroup(cmd *exec.Cmd, foreground bool) error {
+	return cmd.Wait()
+}
+
+func reclaimTerminal() {}
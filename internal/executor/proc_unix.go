@@ -0,0 +1,179 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// forwardedSignals are passed on from tz to the command's process group
+var forwardedSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+
+// terminateSignal asks leftover processes to exit
+var terminateSignal os.Signal = syscall.SIGTERM
+
+// setProcessGroup makes cmd start in its own process group. If the command
+// is attached and tz owns the terminal, the new group becomes the terminal's
+// foreground group so the command can still read from it and receives Ctrl-C
+// directly. It reports whether the terminal was handed over.
+func setProcessGroup(cmd *exec.Cmd, attached bool) bool {
+	attr := &syscall.SysProcAttr{Setpgid: true}
+
+	foreground := false
+	if pgrp, err := terminalGroup(); attached && err == nil && pgrp == syscall.Getpgrp() {
+		attr.Foreground = true
+		attr.Ctty = int(os.Stdin.Fd())
+		foreground = true
+	}
+
+	cmd.SysProcAttr = attr
+	return foreground
+}
+
+// signalGroup sends sig to every process in the group
+func signalGroup(pgid int, sig os.Signal) {
+	if s, ok := sig.(syscall.Signal); ok {
+		syscall.Kill(-pgid, s)
+	}
+}
+
+// setDetached makes cmd start in a new session without a controlling
+// terminal, so closing the terminal doesn't stop it
+func setDetached(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// GroupAlive reports whether any process of the group is still running
+func GroupAlive(pgid int) bool {
+	return syscall.Kill(-pgid, 0) == nil
+}
+
+// ProcessStart returns when the process started, as an opaque string that
+// tells it apart from a later process that got the same PID. It returns ""
+// if the process doesn't exist (or the start can't be found out).
+func ProcessStart(pid int) string {
+	// Linux: the start time in clock ticks since boot, the 22nd field of
+	// /proc/<pid>/stat. The command name before it may contain spaces, so
+	// count from the parenthesis that ends it.
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		if i := strings.LastIndexByte(string(data), ')'); i >= 0 {
+			if fields := strings.Fields(string(data[i+1:])); len(fields) > 19 {
+				return fields[19]
+			}
+		}
+		return ""
+	}
+
+	// Elsewhere ps knows it, to the second
+	out, err := exec.Command("ps", "-o", "lstart=", "-p", fmt.Sprint(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// killedBySignal reports whether a finished command was terminated by a signal
+func killedBySignal(err error) bool {
+	var tzErr *ExitError
+	if errors.As(err, &tzErr) {
+		return tzErr.Signal != nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled()
+}
+
+// waitGroup waits for the started cmd to exit. A command that has the
+// terminal can be stopped from it with Ctrl-Z, which only reaches the
+// command's group. tz then stops as well, so the shell gets the terminal
+// back and sees a stopped job; once tz is continued, so is the command.
+func waitGroup(cmd *exec.Cmd, foreground bool) error {
+	if !foreground {
+		return cmd.Wait()
+	}
+
+	// cmd.Wait doesn't return for stopped processes, so wait for the
+	// process here and only leave its output to cmd.Wait
+	pid := cmd.Process.Pid
+	var status syscall.WaitStatus
+	for {
+		_, err := syscall.Wait4(pid, &status, syscall.WUNTRACED, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return cmd.Wait()
+		}
+		if !status.Stopped() {
+			break
+		}
+		suspend(pid)
+	}
+
+	// The process is gone, so Wait's own error is meaningless
+	cmd.Wait()
+
+	switch {
+	case status.Signaled():
+		return &ExitError{Code: 128 + int(status.Signal()), Signal: status.Signal()}
+	case status.ExitStatus() != 0:
+		return &ExitError{Code: status.ExitStatus()}
+	}
+	return nil
+}
+
+// suspend stops tz while the command's process group pgid is stopped. When
+// tz is continued in the foreground (fg), the command gets the terminal
+// back; continued in the background (bg), it runs without it.
+func suspend(pgid int) {
+	// The stop takes effect asynchronously; SIGCONT tells when it's over
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(cont)
+
+	reclaimTerminal()
+	syscall.Kill(0, syscall.SIGTSTP)
+	<-cont
+
+	if pgrp, err := terminalGroup(); err == nil && pgrp == syscall.Getpgrp() {
+		setTerminalGroup(pgid)
+	}
+	syscall.Kill(-pgid, syscall.SIGCONT)
+}
+
+// reclaimTerminal makes tz's process group the terminal's foreground group
+// again once the command is done
+func reclaimTerminal() {
+	setTerminalGroup(syscall.Getpgrp())
+}
+
+// setTerminalGroup makes pgid the foreground group of the terminal on stdin
+func setTerminalGroup(pgid int) {
+	// tz is a background process while the command has the terminal, and
+	// changing the foreground group from the background raises SIGTTOU
+	signal.Ignore(syscall.SIGTTOU)
+	defer signal.Reset(syscall.SIGTTOU)
+
+	pgrp := int32(pgid)
+	syscall.Syscall(syscall.SYS_IOCTL, os.Stdin.Fd(), uintptr(syscall.TIOCSPGRP), uintptr(unsafe.Pointer(&pgrp)))
+}
+
+// terminalGroup returns the foreground process group of the terminal on stdin
+func terminalGroup() (int, error) {
+	var pgrp int32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, os.Stdin.Fd(), uintptr(syscall.TIOCGPGRP), uintptr(unsafe.Pointer(&pgrp)))
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(pgrp), nil
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"time"
+)
+
+// runGroup runs cmd as the leader of a new process group and waits for it.
+// SIGINT, SIGTERM and SIGHUP received by tz are forwarded to the whole
+// group, and a group that hasn't exited a grace period later is killed.
+// When the command was interrupted, processes it left behind in the group
+// (dev server workers, file watchers) are cleaned up as well. An attached
+// command may take over the terminal, and stopping it with Ctrl-Z suspends
+// tz too; closing stop interrupts the command like SIGTERM would.
+func runGroup(cmd *exec.Cmd, grace time.Duration, attached bool, stop <-chan struct{}) error {
+	foreground := setProcessGroup(cmd, attached)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, forwardedSignals...)
+	defer signal.Stop(sigs)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	pgid := cmd.Process.Pid
+
+	done := make(chan error, 1)
+	go func() {
+		done <- waitGroup(cmd, foreground)
+	}()
+
+	interrupted := false
+	var killTimer <-chan time.Time
+	for {
+		select {
+		case sig := <-sigs:
+			interrupted = true
+			signalGroup(pgid, sig)
+			if killTimer == nil {
+				killTimer = time.After(grace)
+			}
+		case <-stop:
+			stop = nil
+			interrupted = true
+			signalGroup(pgid, terminateSignal)
+			if killTimer == nil {
+				killTimer = time.After(grace)
+			}
+		case <-killTimer:
+			fmt.Fprintf(os.Stderr, "tz: command did not exit within %s, killing it\n", grace)
+			signalGroup(pgid, os.Kill)
+		case err := <-done:
+			// With the terminal's foreground group, Ctrl-C goes straight to
+			// the command; its exit status tells us it was interrupted
+			if killedBySignal(err) {
+				interrupted = true
+			}
+			if interrupted && StopGroup(pgid, grace) {
+				fmt.Fprintln(os.Stderr, "tz: killed processes left behind by the command")
+			}
+			if foreground {
+				reclaimTerminal()
+			}
+			return err
+		}
+	}
+}
+
+// StopGroup makes sure nothing is left of a process group: its processes
+// get SIGTERM, and SIGKILL if any are still running after the grace period.
+// It reports whether SIGKILL was needed.
+func StopGroup(pgid int, grace time.Duration) bool {
+	if !GroupAlive(pgid) {
+		return false
+	}
+
+	signalGroup(pgid, terminateSignal)
+	deadline := time.Now().Add(grace)
+	for time.Now().Before(deadline) {
+		if !GroupAlive(pgid) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	signalGroup(pgid, os.Kill)
+	return true
+}
+
+// StartDetached starts a shell command in a new session, detached from the
+// terminal, with its output going to logFile. It returns the process ID,
+// which is also the ID of the command's process group.
+func StartDetached(command string, opts Options, logFile *os.File) (int, error) {
+	if command == "" {
+		return 0, fmt.Errorf("empty command")
+	}
+
+	cmd := shellCommand(command, opts)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.Stdin = nil
+	setDetached(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	pid := cmd.Process.Pid
+	// Nobody waits for the process; let the OS reap it once tz is gone
+	cmd.Process.Release()
+	return pid, nil
+}
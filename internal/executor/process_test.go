@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package executor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessStart(t *testing.T) {
+	start := ProcessStart(os.Getpid())
+	if start == "" {
+		t.Fatal("ProcessStart() of the test process is empty")
+	}
+	if again := ProcessStart(os.Getpid()); again != start {
+		t.Errorf("ProcessStart() = %q, then %q", start, again)
+	}
+
+	// PIDs are far below this on every system tz runs on
+	if got := ProcessStart(1 << 30); got != "" {
+		t.Errorf("ProcessStart() of a missing process = %q, want empty", got)
+	}
+}
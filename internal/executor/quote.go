@@ -0,0 +1,63 @@
+package executor
+
+import "strings"
+
+// Quote returns arg quoted for a POSIX shell, so the shell passes it on as
+// a single word without expanding anything. Arguments made only of safe
+// characters are returned unchanged to keep commands readable.
+func Quote(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+
+	safe := true
+	for _, r := range arg {
+		if !isSafeRune(r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return arg
+	}
+
+	return "'" + EscapeSingle(arg) + "'"
+}
+
+// EscapeSingle returns arg for use inside single quotes
+func EscapeSingle(arg string) string {
+	// Inside single quotes nothing is special except the closing quote,
+	// which is written as '\''
+	return strings.ReplaceAll(arg, "'", `'\''`)
+}
+
+// EscapeDouble returns arg for use inside double quotes, where $, `, " and
+// \ keep their meaning unless escaped with a backslash
+func EscapeDouble(arg string) string {
+	var b strings.Builder
+	for _, r := range arg {
+		if strings.ContainsRune("$`\"\\", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// QuoteArgs quotes each argument and joins them with spaces
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// isSafeRune reports whether r never needs quoting in a shell word
+func isSafeRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("_-+=@%:,./", r)
+}
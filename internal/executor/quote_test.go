@@ -0,0 +1,86 @@
+package executor
+
+import "testing"
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"", "''"},
+		{"simple", "simple"},
+		{"./internal/...", "./internal/..."},
+		{"--name=value", "--name=value"},
+		{"user@host:path,x+y%", "user@host:path,x+y%"},
+		{"hello world", "'hello world'"},
+		{"TestFoo|TestBar", "'TestFoo|TestBar'"},
+		{"*.go", "'*.go'"},
+		{"$HOME", "'$HOME'"},
+		{"it's", `'it'\''s'`},
+		{"'", `''\'''`},
+		{`a"b`, `'a"b'`},
+		{"a\nb", "'a\nb'"},
+		{"naïve", "'naïve'"},
+	}
+
+	for _, tt := range tests {
+		if got := Quote(tt.arg); got != tt.want {
+			t.Errorf("Quote(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"a"}, "a"},
+		{[]string{"-run", "TestFoo|TestBar", "./..."}, "-run 'TestFoo|TestBar' ./..."},
+		{[]string{"", "x"}, "'' x"},
+	}
+
+	for _, tt := range tests {
+		if got := QuoteArgs(tt.args); got != tt.want {
+			t.Errorf("QuoteArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeSingle(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"hello world", "hello world"},
+		{"$HOME `x` \"y\"", "$HOME `x` \"y\""},
+		{"it's", `it'\''s`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeSingle(tt.arg); got != tt.want {
+			t.Errorf("EscapeSingle(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeDouble(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"hello world", "hello world"},
+		{"it's", "it's"},
+		{"$HOME", `\$HOME`},
+		{"`date`", "\\`date\\`"},
+		{`say "hi"`, `say \"hi\"`},
+		{`C:\dir`, `C:\\dir`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeDouble(tt.arg); got != tt.want {
+			t.Errorf("EscapeDouble(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package filelock
+
+import (
+	"fmt"
+	"os"
+)
+
+// Lock is an exclusive advisory lock held on a file
+type Lock struct {
+	file *os.File
+}
+
+// Acquire blocks until it holds an exclusive lock on the file at path,
+// creating the file if needed
+func Acquire(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := lock(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	return &Lock{file: file}, nil
+}
+
+// Release unlocks and closes the lock file
+func (l *Lock) Release() error {
+	if err := unlock(l.file); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,44 @@
+package filelock
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireExcludes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	first, err := Acquire(path)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	acquired := make(chan *Lock)
+	go func() {
+		second, err := Acquire(path)
+		if err != nil {
+			t.Errorf("second Acquire() error = %v", err)
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Acquire() returned while the lock was held")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := first.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	select {
+	case second := <-acquired:
+		if second != nil {
+			second.Release()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second Acquire() didn't return after Release()")
+	}
+}
@@ -0,0 +1,40 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package filelock
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// Platforms without flock or LockFileEx lock with a marker file next to the
+// locked one, created exclusively. A marker left behind by a crashed
+// process is taken over once it is older than staleAfter.
+const (
+	retryEvery = 10 * time.Millisecond
+	staleAfter = 10 * time.Second
+)
+
+func lock(file *os.File) error {
+	marker := file.Name() + ".lock"
+	for {
+		f, err := os.OpenFile(marker, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			return f.Close()
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+
+		if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) > staleAfter {
+			os.Remove(marker)
+			continue
+		}
+		time.Sleep(retryEvery)
+	}
+}
+
+func unlock(file *os.File) error {
+	return os.Remove(file.Name() + ".lock")
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// TopLevel returns the root of the git work tree containing dir
+func TopLevel(dir string) (string, error) {
+	return run(dir, "rev-parse", "--show-toplevel")
+}
+
+// CurrentBranch returns the name of the branch checked out at dir
+func CurrentBranch(dir string) (string, error) {
+	return run(dir, "branch", "--show-current")
+}
+
+// RemoteURL returns the URL of the origin remote of the repository at dir
+func RemoteURL(dir string) (string, error) {
+	return run(dir, "config", "--get", "remote.origin.url")
+}
+
+// NormalizeRemote turns the different spellings of a remote URL into a
+// stable identity, e.g. both git@github.com:User/repo.git and
+// https://github.com/User/repo become github.com/User/repo
+func NormalizeRemote(url string) string {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return ""
+	}
+
+	if i := strings.Index(url, "://"); i >= 0 {
+		// URL format (https://, ssh://, git://)
+		url = url[i+3:]
+	} else if i := strings.Index(url, ":"); i >= 0 && !strings.Contains(url[:i], "/") {
+		// SCP-like SSH format (git@github.com:user/repo)
+		url = url[:i] + "/" + url[i+1:]
+	}
+
+	// Drop credentials or the ssh user
+	if i := strings.Index(url, "@"); i >= 0 && i < strings.Index(url+"/", "/") {
+		url = url[i+1:]
+	}
+
+	url = strings.TrimRight(url, "/")
+	url = strings.TrimSuffix(url, ".git")
+
+	host, path, _ := strings.Cut(url, "/")
+	// Drop the port, ssh://git@host:22/... and https://host/... are the same repo
+	host, _, _ = strings.Cut(host, ":")
+	return strings.ToLower(host) + "/" + path
+}
+
+// run executes a git subcommand in dir and returns its trimmed output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,159 @@
+package glob
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Expand returns the files matching the glob patterns, relative to dir and
+// sorted. Besides the usual wildcards, "**" matches any number of
+// directories, and a pattern matching a directory stands for every file in
+// it. .git directories are never included.
+func Expand(dir string, patterns []string) ([]string, error) {
+	seen := map[string]bool{}
+
+	add := func(match string) error {
+		return filepath.WalkDir(filepath.Join(dir, match), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			seen[rel] = true
+			return nil
+		})
+	}
+
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+
+		if !strings.Contains(pattern, "**") {
+			matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range matches {
+				rel, err := filepath.Rel(dir, m)
+				if err != nil {
+					return nil, err
+				}
+				if err := add(rel); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		// Only walk the part of the tree the pattern can match
+		segments := strings.Split(pattern, "/")
+		base := 0
+		for base < len(segments) && !strings.ContainsAny(segments[base], "*?[") {
+			base++
+		}
+		root := filepath.Join(dir, filepath.FromSlash(strings.Join(segments[:base], "/")))
+
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == root {
+					// Nothing to match
+					return filepath.SkipAll
+				}
+				return err
+			}
+			if d.IsDir() {
+				if d.Name() == ".git" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			if Match(pattern, filepath.ToSlash(rel)) {
+				seen[rel] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]string, 0, len(seen))
+	for file := range seen {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Exists reports whether anything, file or directory, matches pattern
+// relative to dir. Unlike Expand it doesn't look inside matching
+// directories, so an empty directory counts and a large one is cheap.
+func Exists(dir, pattern string) bool {
+	pattern = filepath.ToSlash(pattern)
+
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		return err == nil && len(matches) > 0
+	}
+
+	found := false
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return nil
+		}
+		if Match(pattern, filepath.ToSlash(rel)) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// Match reports whether the slash-separated name matches pattern, where a
+// "**" segment matches any number of path segments
+func Match(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,113 @@
+package glob
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// tree creates the files (and directories, for names ending in "/") in a
+// temporary directory
+func tree(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if name[len(name)-1] == '/' {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "cmd/tz/main.go", true},
+		{"src/**", "src/a/b.ts", true},
+		{"src/**", "lib/a.ts", false},
+		{"src/**/test/*.ts", "src/test/a.ts", true},
+		{"src/**/test/*.ts", "src/x/y/test/a.ts", true},
+		{"src/**/test/*.ts", "src/x/a.ts", false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	dir := tree(t, "go.sum", "main.go", "cmd/run.go", "web/src/app.ts", "web/src/deep/x.ts", ".git/HEAD", "empty/")
+
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{[]string{"go.sum"}, []string{"go.sum"}},
+		{[]string{"*.go"}, []string{"main.go"}},
+		{[]string{"**/*.go"}, []string{"cmd/run.go", "main.go"}},
+		{[]string{"web"}, []string{"web/src/app.ts", "web/src/deep/x.ts"}},
+		{[]string{"web/**/*.ts"}, []string{"web/src/app.ts", "web/src/deep/x.ts"}},
+		{[]string{"main.go", "*.go"}, []string{"main.go"}},
+		{[]string{"**/HEAD"}, []string{}},
+		{[]string{"empty"}, []string{}},
+		{[]string{"missing/**"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		got, err := Expand(dir, tt.patterns)
+		if err != nil {
+			t.Errorf("Expand(%q) error = %v", tt.patterns, err)
+			continue
+		}
+		want := make([]string, len(tt.want))
+		for i, name := range tt.want {
+			want[i] = filepath.FromSlash(name)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expand(%q) = %q, want %q", tt.patterns, got, want)
+		}
+	}
+}
+
+func TestExists(t *testing.T) {
+	dir := tree(t, "bin/app", "out/", "dist/js/app.js", ".git/HEAD")
+
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"bin/app", true},
+		{"bin", true},
+		{"out", true},
+		{"out/*", false},
+		{"dist/**/*.js", true},
+		{"**/*.css", false},
+		{"**/HEAD", false},
+		{"missing", false},
+	}
+
+	for _, tt := range tests {
+		if got := Exists(dir, tt.pattern); got != tt.want {
+			t.Errorf("Exists(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
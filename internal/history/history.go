@@ -0,0 +1,145 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/totti-rdz/tz/internal/config"
+	"github.com/totti-rdz/tz/internal/filelock"
+)
+
+// Entry is one run of a mapped command, stored as a line of
+// ~/.tz/history.jsonl
+type Entry struct {
+	Project      string        `json:"project"`
+	Name         string        `json:"name"`    // Command name, e.g. "test" or "docker"
+	Command      string        `json:"command"` // Shell command that ran
+	Args         []string      `json:"args,omitempty"`
+	Start        time.Time     `json:"start"`
+	Duration     time.Duration `json:"duration_ns"`
+	ExitCode     int           `json:"exit_code"`
+	Log          string        `json:"log,omitempty"`           // Log file of the run, if it was logged
+	DependencyOf string        `json:"dependency_of,omitempty"` // Command this run was a dependency of
+}
+
+// path returns the path to the history file
+func path() (string, error) {
+	tzDir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(tzDir, "history.jsonl"), nil
+}
+
+// Append adds an entry to the history. The file is locked while writing so
+// concurrent tz processes don't interleave their lines.
+func Append(e Entry) error {
+	historyPath, err := path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		return fmt.Errorf("failed to create .tz directory: %w", err)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	lock, err := filelock.Acquire(historyPath)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	file, err := os.OpenFile(historyPath, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	// A tz that died mid-write leaves a truncated last line; end it so the
+	// new entry isn't glued to it
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			data = append([]byte{'\n'}, data...)
+		}
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// Load returns all history entries, oldest first. Lines that can't be
+// parsed are skipped.
+func Load() ([]Entry, error) {
+	historyPath, err := path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(historyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Recent returns the last n entries that match, oldest first. With n <= 0
+// it returns every matching entry.
+func Recent(n int, match func(Entry) bool) ([]Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Entry
+	for _, e := range entries {
+		if match(e) {
+			matched = append(matched, e)
+		}
+	}
+	if n > 0 && len(matched) > n {
+		matched = matched[len(matched)-n:]
+	}
+	return matched, nil
+}
+
+// Last returns the most recent command run in the project, skipping runs
+// that were only dependencies of another command
+func Last(projectPath string) (Entry, bool, error) {
+	entries, err := Recent(1, func(e Entry) bool {
+		return e.Project == projectPath && e.DependencyOf == ""
+	})
+	if err != nil || len(entries) == 0 {
+		return Entry{}, false, err
+	}
+	return entries[0], true, nil
+}
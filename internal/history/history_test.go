@@ -0,0 +1,152 @@
+package history
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// names returns the command names of the entries
+func names(entries []Entry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	want := Entry{
+		Project:  "/p",
+		Name:     "test",
+		Command:  "go test ./...",
+		Args:     []string{"-run", "TestX"},
+		Start:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Duration: 1500 * time.Millisecond,
+		ExitCode: 1,
+	}
+	if err := Append(want); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if err := Append(Entry{Project: "/p", Name: "build"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	entries, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := names(entries); !reflect.DeepEqual(got, []string{"test", "build"}) {
+		t.Fatalf("Load() names = %v, want [test build]", got)
+	}
+	if !reflect.DeepEqual(entries[0], want) {
+		t.Errorf("Load()[0] = %+v, want %+v", entries[0], want)
+	}
+}
+
+func TestLoadWithoutHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	entries, err := Load()
+	if err != nil || entries != nil {
+		t.Errorf("Load() = %v, %v, want no entries", entries, err)
+	}
+}
+
+func TestRecent(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		if err := Append(Entry{Project: "/p", Name: name, ExitCode: int(name[0]) % 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all := func(Entry) bool { return true }
+	failed := func(e Entry) bool { return e.ExitCode != 0 }
+
+	tests := []struct {
+		name  string
+		n     int
+		match func(Entry) bool
+		want  []string
+	}{
+		{"last two", 2, all, []string{"d", "e"}},
+		{"more than there are", 10, all, []string{"a", "b", "c", "d", "e"}},
+		{"everything", 0, all, []string{"a", "b", "c", "d", "e"}},
+		{"last two matching", 2, failed, []string{"c", "e"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Recent(tt.n, tt.match)
+			if err != nil {
+				t.Fatalf("Recent() error = %v", err)
+			}
+			if got := names(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Recent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLastSkipsDependencies(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, e := range []Entry{
+		{Project: "/p", Name: "test"},
+		{Project: "/p", Name: "build"},
+		{Project: "/p", Name: "gen", DependencyOf: "build"},
+		{Project: "/other", Name: "dev"},
+	} {
+		if err := Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	last, ok, err := Last("/p")
+	if err != nil || !ok || last.Name != "build" {
+		t.Errorf("Last(/p) = %q, %v, %v, want build", last.Name, ok, err)
+	}
+	if _, ok, _ := Last("/new"); ok {
+		t.Error("Last(/new) found an entry in a project without history")
+	}
+}
+
+func TestTruncatedLastLine(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := Append(Entry{Project: "/p", Name: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a tz that died while writing its entry
+	historyPath, err := path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(historyPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"project":"/p","name":"bui`)
+	file.Close()
+
+	entries, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := names(entries); !reflect.DeepEqual(got, []string{"test"}) {
+		t.Errorf("Load() = %v, want [test]", got)
+	}
+
+	if err := Append(Entry{Project: "/p", Name: "lint"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	entries, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := names(entries); !reflect.DeepEqual(got, []string{"test", "lint"}) {
+		t.Errorf("Load() after Append = %v, want [test lint]", got)
+	}
+}
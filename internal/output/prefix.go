@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// palette holds the ANSI colors given to prefixes, in order
+var palette = []string{"36", "33", "35", "32", "34", "31"}
+
+// Colorize wraps text in the i-th palette color, unless stdout isn't a
+// terminal or NO_COLOR is set
+func Colorize(i int, text string) string {
+	if !colorEnabled() {
+		return text
+	}
+	return fmt.Sprintf("\033[%sm%s\033[0m", palette[i%len(palette)], text)
+}
+
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// PrefixWriter writes every line it receives to an underlying writer with
+// a prefix in front. Writers sharing a mutex can write to the same output
+// without their lines getting mixed up; the mutex also makes a single
+// writer safe to use for a command's stdout and stderr at once.
+type PrefixWriter struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+// NewPrefixWriter returns a PrefixWriter writing to out
+func NewPrefixWriter(out io.Writer, mu *sync.Mutex, prefix string) *PrefixWriter {
+	return &PrefixWriter{out: out, mu: mu, prefix: prefix}
+}
+
+// Write buffers p and writes out the complete lines in it
+func (w *PrefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes out a last line that didn't end in a newline
+func (w *PrefixWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+// Printf writes a formatted line of tz's own, with the prefix
+func (w *PrefixWriter) Printf(format string, args ...any) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.writeLine([]byte(fmt.Sprintf(format, args...) + "\n"))
+}
+
+// writeLine writes a line with the prefix; the caller holds the mutex
+func (w *PrefixWriter) writeLine(line []byte) error {
+	if _, err := io.WriteString(w.out, w.prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(line)
+	return err
+}
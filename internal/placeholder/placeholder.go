@@ -0,0 +1,201 @@
+package placeholder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Placeholders that can appear in a mapped command:
+//
+//	{1}, {2}, ...   positional arguments
+//	{@}, {args}     all arguments
+//	{project}       name of the project directory
+//	{root}          absolute path of the project root
+//	{cwd}           directory tz was run from
+//	{branch}        current git branch
+//
+// Any placeholder can carry a default used when the value is missing or
+// empty, e.g. {1:main} or {branch:HEAD}. Other braces such as ${HOME} or
+// {a,b} are left alone, so shell syntax keeps working.
+//
+// Values are quoted for where the placeholder stands: "{1}" must not get
+// another layer of quotes, but its value still mustn't end the string.
+
+// Names lists the named placeholders
+var Names = []string{"project", "root", "cwd", "branch"}
+
+// Lookup returns the value of a named placeholder
+type Lookup func(name string) (string, error)
+
+// Context is the kind of shell quoting a placeholder appears in
+type Context int
+
+const (
+	Unquoted     Context = iota // {1}
+	SingleQuoted                // '{1}'
+	DoubleQuoted                // "{1}"
+)
+
+// Quote makes a value safe to insert into a command in the given context
+type Quote func(value string, ctx Context) string
+
+// token is a placeholder found in a command
+type token struct {
+	start, end int    // Byte range of the placeholder, braces included
+	name       string // "1", "@", "project", ...
+	def        string
+	hasDefault bool
+	ctx        Context
+}
+
+// HasPlaceholders reports whether command contains any placeholder
+func HasPlaceholders(command string) bool {
+	return len(parse(command)) > 0
+}
+
+// Expand replaces the placeholders in command. Positional and variadic
+// placeholders take their values from args, named ones from lookup; every
+// value goes through quote. Defaults are inserted as written. If there is
+// no {@}, arguments past the highest positional placeholder are appended
+// to the end, like for commands without placeholders.
+func Expand(command string, args []string, lookup Lookup, quote Quote) (string, error) {
+	tokens := parse(command)
+	if len(tokens) == 0 {
+		return command, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	highest := 0
+	variadic := false
+
+	for _, t := range tokens {
+		b.WriteString(command[last:t.start])
+		last = t.end
+
+		var value string
+		found := false
+		switch {
+		case t.name == "@" || t.name == "args":
+			variadic = true
+			found = len(args) > 0
+			value = join(args, t.ctx, quote)
+		case isPositional(t.name):
+			n, _ := strconv.Atoi(t.name)
+			if n > highest {
+				highest = n
+			}
+			if n <= len(args) {
+				found = true
+				value = quote(args[n-1], t.ctx)
+			}
+		default:
+			v, err := lookup(t.name)
+			if err != nil && !t.hasDefault {
+				return "", fmt.Errorf("failed to resolve {%s}: %w", t.name, err)
+			}
+			if v != "" {
+				found = true
+				value = quote(v, t.ctx)
+			}
+		}
+
+		if !found {
+			if !t.hasDefault {
+				if isPositional(t.name) {
+					return "", fmt.Errorf("missing argument {%s}", t.name)
+				}
+				if t.name != "@" && t.name != "args" {
+					return "", fmt.Errorf("no value for {%s}", t.name)
+				}
+			}
+			value = t.def
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(command[last:])
+
+	expanded := b.String()
+	if !variadic && len(args) > highest {
+		expanded += " " + join(args[highest:], Unquoted, quote)
+	}
+	return expanded, nil
+}
+
+// join quotes each value and joins them with spaces
+func join(values []string, ctx Context, quote Quote) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote(value, ctx)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// parse finds the placeholders in command, following the shell's quotes
+// to tell which context each one is in
+func parse(command string) []token {
+	var tokens []token
+	ctx := Unquoted
+	for i := 0; i < len(command); i++ {
+		switch c := command[i]; {
+		case c == '\\' && ctx != SingleQuoted:
+			// The escaped character is taken literally
+			i++
+			continue
+		case c == '\'' && ctx != DoubleQuoted:
+			if ctx == SingleQuoted {
+				ctx = Unquoted
+			} else {
+				ctx = SingleQuoted
+			}
+			continue
+		case c == '"' && ctx != SingleQuoted:
+			if ctx == DoubleQuoted {
+				ctx = Unquoted
+			} else {
+				ctx = DoubleQuoted
+			}
+			continue
+		}
+
+		if command[i] != '{' || (i > 0 && command[i-1] == '$') {
+			continue
+		}
+
+		end := strings.IndexByte(command[i:], '}')
+		if end < 0 {
+			break
+		}
+		end += i
+
+		inner := command[i+1 : end]
+		name, def, hasDefault := strings.Cut(inner, ":")
+		if !isPlaceholderName(name) {
+			continue
+		}
+
+		tokens = append(tokens, token{start: i, end: end + 1, name: name, def: def, hasDefault: hasDefault, ctx: ctx})
+		i = end
+	}
+	return tokens
+}
+
+// isPlaceholderName reports whether name is a known placeholder
+func isPlaceholderName(name string) bool {
+	if name == "@" || name == "args" || isPositional(name) {
+		return true
+	}
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isPositional reports whether name is a positional placeholder (1-based)
+func isPositional(name string) bool {
+	n, err := strconv.Atoi(name)
+	return err == nil && n >= 1 && name[0] != '+'
+}
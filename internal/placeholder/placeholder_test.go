@@ -0,0 +1,121 @@
+package placeholder
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHasPlaceholders(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"go test ./...", false},
+		{"go test {args}", true},
+		{"echo {1}", true},
+		{"echo {1:main}", true},
+		{"echo {branch}", true},
+		{"echo ${HOME}", false},
+		{"cp file.{a,b} dist", false},
+		{"echo {0}", false},
+		{"echo {+1}", false},
+		{"echo {unknown}", false},
+		{"echo {1", false},
+	}
+
+	for _, tt := range tests {
+		if got := HasPlaceholders(tt.command); got != tt.want {
+			t.Errorf("HasPlaceholders(%q) = %v, want %v", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []Context
+	}{
+		{"echo {1}", []Context{Unquoted}},
+		{`echo "{1}"`, []Context{DoubleQuoted}},
+		{"echo '{1}'", []Context{SingleQuoted}},
+		{`echo "it's {1}" {2}`, []Context{DoubleQuoted, Unquoted}},
+		{`echo 'say "{1}"' {2}`, []Context{SingleQuoted, Unquoted}},
+		{`echo \"{1}`, []Context{Unquoted}},
+		{`echo "a\"{1}"`, []Context{DoubleQuoted}},
+		{`echo 'a\'{1}`, []Context{Unquoted}},
+	}
+
+	for _, tt := range tests {
+		tokens := parse(tt.command)
+		if len(tokens) != len(tt.want) {
+			t.Errorf("parse(%q) found %d placeholders, want %d", tt.command, len(tokens), len(tt.want))
+			continue
+		}
+		for i, token := range tokens {
+			if token.ctx != tt.want[i] {
+				t.Errorf("parse(%q): placeholder %d has context %d, want %d", tt.command, i+1, token.ctx, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	// Marks the quoting instead of quoting, to show which context was used
+	quote := func(value string, ctx Context) string {
+		switch ctx {
+		case SingleQuoted:
+			return "s(" + value + ")"
+		case DoubleQuoted:
+			return "d(" + value + ")"
+		}
+		return "u(" + value + ")"
+	}
+	lookup := func(name string) (string, error) {
+		switch name {
+		case "project":
+			return "api", nil
+		case "branch":
+			return "", fmt.Errorf("not a git repository")
+		}
+		return "", nil
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{"no placeholders", "go test", []string{"./..."}, "go test", false},
+		{"positional", "echo {2} {1}", []string{"a", "b"}, "echo u(b) u(a)", false},
+		{"extra args appended", "echo {1}", []string{"a", "b", "c"}, "echo u(a) u(b) u(c)", false},
+		{"all args", "echo {@} end", []string{"a", "b"}, "echo u(a) u(b) end", false},
+		{"args alias", "echo {args}", []string{"a"}, "echo u(a)", false},
+		{"no args for {@}", "echo {@}", nil, "echo ", false},
+		{"quoted", `echo "{1}" '{2}'`, []string{"a b", "c"}, `echo "d(a b)" 's(c)'`, false},
+		{"quoted all args", `echo "{@}"`, []string{"a", "b"}, `echo "d(a) d(b)"`, false},
+		{"default", "git push {1:origin}", nil, "git push origin", false},
+		{"default unused", "git push {1:origin}", []string{"up"}, "git push u(up)", false},
+		{"empty argument", "echo {1}", []string{""}, "echo u()", false},
+		{"missing argument", "echo {1}", nil, "", true},
+		{"named", "echo {project}", nil, "echo u(api)", false},
+		{"named empty", "echo {cwd}", nil, "", true},
+		{"named empty with default", "echo {cwd:.}", nil, "echo .", false},
+		{"lookup error", "echo {branch}", nil, "", true},
+		{"lookup error with default", "echo {branch:HEAD}", nil, "echo HEAD", false},
+		{"shell braces kept", "echo ${HOME} {a,b} {1}", []string{"x"}, "echo ${HOME} {a,b} u(x)", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.command, tt.args, lookup, quote)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expand(%q, %q) error = %v, wantErr %v", tt.command, tt.args, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Expand(%q, %q) = %q, want %q", tt.command, tt.args, got, tt.want)
+			}
+		})
+	}
+}
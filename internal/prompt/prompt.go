@@ -22,6 +22,11 @@ func Confirm(message string) bool {
 	return response == "y" || response == "yes"
 }
 
+// IsInteractive reports whether stdin is a terminal that can answer prompts
+func IsInteractive() bool {
+	return isTerminal(os.Stdin)
+}
+
 // ConfirmCommand asks the user if they want to run a suggested command
 func ConfirmCommand(projectType, commandName, command string) bool {
 	fmt.Printf("\nNo mapping found for '%s' in this project.\n", commandName)
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package prompt
+
+import "os"
+
+// isTerminal reports whether f refers to a terminal. Without a way to ask
+// the OS, any character device is assumed to be one.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,166 @@
+package runlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+// Keep is how many logs are kept per command; older ones are deleted
+const Keep = 20
+
+// MaxSize is the size a single log may grow to. Output past it still
+// reaches the terminal but is left out of the log.
+const MaxSize = 10 << 20
+
+// timeFormat is the timestamp in log file names; it sorts chronologically
+const timeFormat = "20060102-150405.000"
+
+// File is the log of one run. Writes never fail, so a full disk or the size
+// limit can't break the command whose output is being logged. A command's
+// stdout and stderr are copied in separately, so writes are serialized.
+type File struct {
+	mu        sync.Mutex
+	file      *os.File
+	path      string
+	written   int64
+	truncated bool
+}
+
+// Create starts a new log for a run of the named command in the project,
+// as ~/.tz/logs/<project>/<name>-<timestamp>.log, and deletes the oldest
+// logs of the command beyond Keep
+func Create(projectPath, name string) (*File, error) {
+	dir, err := config.ProjectDir("logs", projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.log", name, time.Now().Format(timeFormat)))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+
+	rotate(dir, name)
+	return &File{file: file, path: path}, nil
+}
+
+// Write adds p to the log, up to MaxSize
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.truncated {
+		return len(p), nil
+	}
+
+	if remaining := MaxSize - f.written; int64(len(p)) > remaining {
+		f.file.Write(p[:remaining])
+		fmt.Fprintf(f.file, "\n[tz: log truncated at %d MB]\n", MaxSize>>20)
+		f.written = MaxSize
+		f.truncated = true
+		return len(p), nil
+	}
+
+	n, _ := f.file.Write(p)
+	f.written += int64(n)
+	return len(p), nil
+}
+
+// Path returns where the log is stored
+func (f *File) Path() string {
+	return f.path
+}
+
+// Close closes the log file
+func (f *File) Close() error {
+	return f.file.Close()
+}
+
+// Last returns the most recent log in the project, optionally only of the
+// named command
+func Last(projectPath, name string) (string, error) {
+	dir, err := config.ProjectDir("logs", projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	pattern := "*.log"
+	if name != "" {
+		pattern = name + "-*.log"
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return "", err
+	}
+
+	var last string
+	var lastTime time.Time
+	for _, path := range paths {
+		if name != "" && !isLogOf(filepath.Base(path), name) {
+			continue
+		}
+		started, ok := startTime(filepath.Base(path))
+		if ok && (last == "" || started.After(lastTime)) {
+			last, lastTime = path, started
+		}
+	}
+
+	if last == "" {
+		return "", fmt.Errorf("no logs for this project yet\n\nTip: Add --log to a mapped command to keep its output")
+	}
+	return last, nil
+}
+
+// rotate deletes all but the newest Keep logs of the named command
+func rotate(dir, name string) {
+	paths, err := filepath.Glob(filepath.Join(dir, name+"-*.log"))
+	if err != nil {
+		return
+	}
+
+	var logs []string
+	for _, path := range paths {
+		// "test-*" also matches the logs of a command named "test-e2e"
+		if isLogOf(filepath.Base(path), name) {
+			logs = append(logs, path)
+		}
+	}
+	if len(logs) <= Keep {
+		return
+	}
+
+	sort.Strings(logs)
+	for _, path := range logs[:len(logs)-Keep] {
+		os.Remove(path)
+	}
+}
+
+// isLogOf reports whether the file name is a log of the named command
+func isLogOf(fileName, name string) bool {
+	if !strings.HasPrefix(fileName, name+"-") {
+		return false
+	}
+	_, ok := startTime(fileName)
+	return ok && len(fileName) == len(name)+1+len(timeFormat)+len(".log")
+}
+
+// startTime parses the timestamp out of a log file name
+func startTime(fileName string) (time.Time, bool) {
+	stamp := strings.TrimSuffix(fileName, ".log")
+	if len(stamp) < len(timeFormat) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(timeFormat, stamp[len(stamp)-len(timeFormat):], time.Local)
+	return t, err == nil
+}
@@ -0,0 +1,164 @@
+package runlog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/totti-rdz/tz/internal/config"
+)
+
+// seed creates n empty logs of the named command, a minute apart, the
+// last one at end, and returns their file names oldest first
+func seed(t *testing.T, dir, name string, n int, end time.Time) []string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	start := end.Add(-time.Duration(n-1) * time.Minute)
+	var names []string
+	for i := 0; i < n; i++ {
+		fileName := fmt.Sprintf("%s-%s.log", name, start.Add(time.Duration(i)*time.Minute).Format(timeFormat))
+		if err := os.WriteFile(filepath.Join(dir, fileName), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, fileName)
+	}
+	return names
+}
+
+// logs returns the file names in dir, sorted
+func logs(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestCreateRotates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	dir, err := config.ProjectDir("logs", projectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hourAgo := time.Now().Add(-time.Hour)
+	old := seed(t, dir, "test", Keep+5, hourAgo)
+	others := seed(t, dir, "test-e2e", 3, hourAgo)
+
+	f, err := Create(projectPath, "test")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	f.Close()
+
+	// The newest Keep logs of test remain, counting the new one; the logs
+	// of test-e2e share the prefix but aren't touched
+	want := append(append([]string{}, old[len(old)-Keep+1:]...), filepath.Base(f.Path()))
+	want = append(want, others...)
+	sort.Strings(want)
+	if got := logs(t, dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("logs after Create() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCreateKeepsFewLogs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	dir, err := config.ProjectDir("logs", projectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := seed(t, dir, "build", 3, time.Now().Add(-time.Hour))
+
+	f, err := Create(projectPath, "build")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	f.Close()
+
+	if got := logs(t, dir); len(got) != len(old)+1 {
+		t.Errorf("logs after Create() = %v, want all %d kept", got, len(old)+1)
+	}
+}
+
+func TestWriteTruncatesAtMaxSize(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	f, err := Create(t.TempDir(), "dev")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	chunk := bytes.Repeat([]byte("x"), 1<<20)
+	for written := 0; written <= MaxSize; written += len(chunk) {
+		if n, err := f.Write(chunk); n != len(chunk) || err != nil {
+			t.Fatalf("Write() = %d, %v, want %d, nil", n, err, len(chunk))
+		}
+	}
+	// Writes past the limit still succeed, so the command isn't affected
+	if n, err := f.Write([]byte("more")); n != 4 || err != nil {
+		t.Fatalf("Write() after truncation = %d, %v, want 4, nil", n, err)
+	}
+	f.Close()
+
+	data, err := os.ReadFile(f.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := fmt.Sprintf("\n[tz: log truncated at %d MB]\n", MaxSize>>20)
+	if len(data) != MaxSize+len(note) {
+		t.Errorf("log size = %d, want %d", len(data), MaxSize+len(note))
+	}
+	if !strings.HasSuffix(string(data), note) {
+		t.Errorf("log doesn't end with the truncation note")
+	}
+}
+
+func TestLast(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectPath := t.TempDir()
+	dir, err := config.ProjectDir("logs", projectPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Last(projectPath, ""); err == nil {
+		t.Error("Last() without logs succeeded")
+	}
+
+	hourAgo := time.Now().Add(-time.Hour)
+	tests := seed(t, dir, "test", 2, hourAgo)
+	e2e := seed(t, dir, "test-e2e", 1, hourAgo.Add(time.Minute))
+
+	for _, tt := range []struct {
+		name string
+		want string
+	}{
+		{"test", tests[1]},
+		{"test-e2e", e2e[0]},
+		{"", e2e[0]},
+	} {
+		got, err := Last(projectPath, tt.name)
+		if err != nil {
+			t.Errorf("Last(%q) error = %v", tt.name, err)
+			continue
+		}
+		if filepath.Base(got) != tt.want {
+			t.Errorf("Last(%q) = %s, want %s", tt.name, filepath.Base(got), tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph maps each task to the tasks it depends on
+type Graph map[string][]string
+
+// Build collects the graph of root and everything it depends on, directly
+// or indirectly. deps returns the direct dependencies of a task. Build
+// fails if the dependencies form a cycle.
+func Build(root string, deps func(name string) ([]string, error)) (Graph, error) {
+	return BuildAll([]string{root}, deps)
+}
+
+// BuildAll collects one graph of several roots and everything they depend
+// on. Tasks shared between the roots appear once.
+func BuildAll(roots []string, deps func(name string) ([]string, error)) (Graph, error) {
+	g := Graph{}
+
+	// Depth-first walk; the path is kept to report cycles
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, n := range path {
+				if n == name {
+					start = i
+				}
+			}
+			cycle := append(path[start:], name)
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		names, err := deps(name)
+		if err != nil {
+			return err
+		}
+		g[name] = names
+		for _, dep := range names {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, root := range roots {
+		if err := visit(root); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Without returns the graph without the named task. Nothing may depend on
+// it, which holds for the root of a graph from Build.
+func (g Graph) Without(name string) Graph {
+	rest := Graph{}
+	for task, deps := range g {
+		if task != name {
+			rest[task] = deps
+		}
+	}
+	return rest
+}
+
+// Order returns the tasks in an order that puts every task after its
+// dependencies. Ties are broken alphabetically so the order is stable.
+func (g Graph) Order() []string {
+	var order []string
+	done := map[string]bool{}
+
+	for len(order) < len(g) {
+		var ready []string
+		for task, deps := range g {
+			if !done[task] && g.satisfied(deps, done) {
+				ready = append(ready, task)
+			}
+		}
+		if len(ready) == 0 {
+			// Only possible with a cycle, which Build rules out
+			break
+		}
+		sort.Strings(ready)
+		for _, task := range ready {
+			done[task] = true
+		}
+		order = append(order, ready...)
+	}
+
+	return order
+}
+
+// Run runs every task once all of its dependencies have succeeded. Tasks
+// that are ready at the same time run concurrently. After a failure no new
+// tasks are started; Run waits for the running ones and returns the first
+// failure.
+func (g Graph) Run(run func(name string) error) error {
+	type result struct {
+		name string
+		err  error
+	}
+
+	done := map[string]bool{}
+	started := map[string]bool{}
+	results := make(chan result)
+	running := 0
+	var failed error
+
+	for {
+		if failed == nil {
+			for _, task := range g.Order() {
+				if !started[task] && g.satisfied(g[task], done) {
+					started[task] = true
+					running++
+					go func() {
+						results <- result{task, run(task)}
+					}()
+				}
+			}
+		}
+
+		if running == 0 {
+			return failed
+		}
+
+		r := <-results
+		running--
+		if r.err != nil {
+			if failed == nil {
+				failed = r.err
+			}
+			continue
+		}
+		done[r.name] = true
+	}
+}
+
+// satisfied reports whether all deps are done. Dependencies that aren't
+// part of the graph don't hold anything up.
+func (g Graph) satisfied(deps []string, done map[string]bool) bool {
+	for _, dep := range deps {
+		if _, ok := g[dep]; ok && !done[dep] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package tasks
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// depsOf returns a dependency lookup backed by a fixed table
+func depsOf(table map[string][]string) func(string) ([]string, error) {
+	return func(name string) ([]string, error) {
+		return table[name], nil
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		root    string
+		table   map[string][]string
+		want    Graph
+		wantErr string
+	}{
+		{
+			name:  "no dependencies",
+			root:  "dev",
+			table: map[string][]string{},
+			want:  Graph{"dev": nil},
+		},
+		{
+			name:  "chain",
+			root:  "e2e",
+			table: map[string][]string{"e2e": {"build"}, "build": {"install"}},
+			want:  Graph{"e2e": {"build"}, "build": {"install"}, "install": nil},
+		},
+		{
+			name:  "diamond",
+			root:  "e2e",
+			table: map[string][]string{"e2e": {"build", "db"}, "build": {"install"}, "db": {"install"}},
+			want:  Graph{"e2e": {"build", "db"}, "build": {"install"}, "db": {"install"}, "install": nil},
+		},
+		{
+			name:  "unrelated tasks left out",
+			root:  "build",
+			table: map[string][]string{"build": {"install"}, "lint": {"install"}},
+			want:  Graph{"build": {"install"}, "install": nil},
+		},
+		{
+			name:    "self cycle",
+			root:    "build",
+			table:   map[string][]string{"build": {"build"}},
+			wantErr: "dependency cycle: build -> build",
+		},
+		{
+			name:    "cycle",
+			root:    "e2e",
+			table:   map[string][]string{"e2e": {"build"}, "build": {"install"}, "install": {"build"}},
+			wantErr: "dependency cycle: build -> install -> build",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Build(tt.root, depsOf(tt.table))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Build() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildAll(t *testing.T) {
+	table := map[string][]string{"api": {"install"}, "web": {"install", "codegen"}}
+	got, err := BuildAll([]string{"api", "web"}, depsOf(table))
+	if err != nil {
+		t.Fatalf("BuildAll() error = %v", err)
+	}
+	want := Graph{"api": {"install"}, "web": {"install", "codegen"}, "install": nil, "codegen": nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildAll() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildLookupError(t *testing.T) {
+	lookupErr := errors.New("broken config")
+	_, err := Build("build", func(string) ([]string, error) { return nil, lookupErr })
+	if !errors.Is(err, lookupErr) {
+		t.Errorf("Build() error = %v, want %v", err, lookupErr)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph Graph
+		want  []string
+	}{
+		{"empty", Graph{}, nil},
+		{"single", Graph{"dev": nil}, []string{"dev"}},
+		{"chain", Graph{"e2e": {"build"}, "build": {"install"}, "install": nil}, []string{"install", "build", "e2e"}},
+		{"ties alphabetical", Graph{"c": nil, "a": nil, "b": nil}, []string{"a", "b", "c"}},
+		{
+			"diamond",
+			Graph{"e2e": {"db", "build"}, "build": {"install"}, "db": {"install"}, "install": nil},
+			[]string{"install", "build", "db", "e2e"},
+		},
+		{"outside dependency", Graph{"build": {"install"}}, []string{"build"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.graph.Order(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithout(t *testing.T) {
+	g := Graph{"e2e": {"build"}, "build": nil}
+	got := g.Without("e2e")
+	if want := (Graph{"build": nil}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Without() = %v, want %v", got, want)
+	}
+	if len(g) != 2 {
+		t.Errorf("Without() changed the original graph: %v", g)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		graph   Graph
+		fail    string   // Task that fails
+		wantRun []string // Tasks that run, sorted
+		wantErr bool
+	}{
+		{
+			name:    "all succeed",
+			graph:   Graph{"e2e": {"build", "db"}, "build": {"install"}, "db": nil, "install": nil},
+			wantRun: []string{"build", "db", "e2e", "install"},
+		},
+		{
+			name:    "failure stops dependents",
+			graph:   Graph{"e2e": {"build"}, "build": {"install"}, "install": nil},
+			fail:    "build",
+			wantRun: []string{"build", "install"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			done := map[string]bool{}
+			var ran []string
+
+			err := tt.graph.Run(func(name string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, dep := range tt.graph[name] {
+					if !done[dep] {
+						t.Errorf("%s ran before its dependency %s", name, dep)
+					}
+				}
+				ran = append(ran, name)
+				if name == tt.fail {
+					return errors.New(name + " failed")
+				}
+				done[name] = true
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), tt.fail) {
+				t.Errorf("Run() error = %v, want the failure of %s", err, tt.fail)
+			}
+
+			got := append([]string(nil), ran...)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantRun) {
+				t.Errorf("Run() ran %v, want %v", got, tt.wantRun)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package watch
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/totti-rdz/tz/internal/glob"
+)
+
+// DefaultIgnored are directories that are never watched: version control
+// data, dependencies and build output that commands write themselves
+var DefaultIgnored = []string{".git", "node_modules", "target", "dist"}
+
+// ignoreRule is one pattern from .gitignore
+type ignoreRule struct {
+	pattern  string
+	anchored bool // Matched against the whole path instead of the name
+	dirOnly  bool // Only matches directories
+}
+
+// Ignore decides which paths a Watcher skips
+type Ignore struct {
+	rules []ignoreRule
+}
+
+// LoadIgnore returns the default ignores plus the patterns of root's
+// .gitignore. Negated patterns ("!keep.txt") are not supported and skipped.
+func LoadIgnore(root string) *Ignore {
+	ig := &Ignore{}
+	for _, name := range DefaultIgnored {
+		ig.rules = append(ig.rules, ignoreRule{pattern: name, dirOnly: true})
+	}
+
+	file, err := os.Open(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return ig
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		// A slash anywhere but at the end ties the pattern to the root
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		rule.pattern = line
+		ig.rules = append(ig.rules, rule)
+	}
+
+	return ig
+}
+
+// Add ignores the slash-separated glob patterns, relative to the watched
+// root, e.g. the outputs a command writes itself
+func (ig *Ignore) Add(patterns ...string) {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "/"), "/")
+		if pattern != "" {
+			ig.rules = append(ig.rules, ignoreRule{pattern: pattern, anchored: true})
+		}
+	}
+}
+
+// Ignored reports whether the slash-separated path (relative to the
+// watched root) is skipped. Paths under an ignored directory are never
+// asked about, since the walk doesn't enter it.
+func (ig *Ignore) Ignored(rel string, isDir bool) bool {
+	for _, rule := range ig.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.anchored {
+			if glob.Match(rule.pattern, rel) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(rule.pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestIgnored(t *testing.T) {
+	root := t.TempDir()
+	gitignore := "# build output\n*.log\n/coverage/\ntmp/\n!keep.log\n"
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(gitignore), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ig := LoadIgnore(root)
+	ig.Add("bin/app", "out/", "gen/**/*.pb.go")
+
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{".git", true, true},
+		{"node_modules", true, true},
+		{"web/node_modules", true, true},
+		{"dist", true, true},
+		{"dist", false, false},
+		{"debug.log", false, true},
+		{"keep.log", false, true},
+		{"coverage", true, true},
+		{"web/coverage", true, false},
+		{"tmp", true, true},
+		{"tmp", false, false},
+		{"bin/app", false, true},
+		{"bin/tool", false, false},
+		{"out", true, true},
+		{"gen/api/v1/api.pb.go", false, true},
+		{"gen/api/v1/api.go", false, false},
+		{"main.go", false, false},
+		{"src", true, false},
+	}
+
+	for _, tt := range tests {
+		if got := ig.Ignored(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q, %v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotSkipsIgnored(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"main.go", ".git/HEAD", "node_modules/x/index.js", "bin/app", "src/lib.go"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for path := range New(root, "bin/app").snapshot() {
+		got = append(got, path)
+	}
+	sort.Strings(got)
+
+	if want := []string{"main.go", "src/lib.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshot() = %v, want %v", got, want)
+	}
+}
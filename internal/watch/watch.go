@@ -0,0 +1,143 @@
+package watch
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileState is what polling compares to notice a change
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher notices file changes under a directory by polling it. It needs
+// no native file notification support, which keeps it portable.
+type Watcher struct {
+	Root     string        // Directory to watch
+	Interval time.Duration // Time between polls
+	Debounce time.Duration // Quiet time after a change before it is reported
+	ignore   *Ignore
+}
+
+// New returns a Watcher for root that skips the default ignored
+// directories, whatever root's .gitignore lists and the extra glob
+// patterns (relative to root)
+func New(root string, ignored ...string) *Watcher {
+	ignore := LoadIgnore(root)
+	ignore.Add(ignored...)
+	return &Watcher{
+		Root:     root,
+		Interval: 300 * time.Millisecond,
+		Debounce: 300 * time.Millisecond,
+		ignore:   ignore,
+	}
+}
+
+// Watch polls for changes until quit is closed. Each batch of changed
+// paths (relative to Root) is sent once no further changes happened for
+// the debounce period, so saving many files at once triggers one batch.
+func (w *Watcher) Watch(quit <-chan struct{}) <-chan []string {
+	changes := make(chan []string)
+
+	go func() {
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		previous := w.snapshot()
+		pending := map[string]bool{}
+		var lastChange time.Time
+
+		for {
+			select {
+			case <-quit:
+				return
+			case <-ticker.C:
+			}
+
+			current := w.snapshot()
+			changed := diff(previous, current)
+			previous = current
+
+			if len(changed) > 0 {
+				for _, path := range changed {
+					pending[path] = true
+				}
+				lastChange = time.Now()
+				continue
+			}
+			if len(pending) == 0 || time.Since(lastChange) < w.Debounce {
+				continue
+			}
+
+			batch := make([]string, 0, len(pending))
+			for path := range pending {
+				batch = append(batch, path)
+			}
+			sort.Strings(batch)
+
+			select {
+			case changes <- batch:
+				pending = map[string]bool{}
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return changes
+}
+
+// snapshot records the state of every file that isn't ignored
+func (w *Watcher) snapshot() map[string]fileState {
+	files := map[string]fileState{}
+
+	filepath.WalkDir(w.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can disappear while we walk; skip what we can't read
+			return nil
+		}
+		rel, err := filepath.Rel(w.Root, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if w.ignore.Ignored(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[rel] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+
+	return files
+}
+
+// diff returns the paths that were added, removed or modified
+func diff(before, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		if old, ok := before[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}